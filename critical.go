@@ -0,0 +1,188 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag
+
+import (
+	"fmt"
+	"math"
+)
+
+// CriticalPathOptions configures the weights used by CriticalPath.
+type CriticalPathOptions struct {
+	// VertexWeight return the duration of a vertex. If both VertexWeight
+	// and EdgeWeight are nil, every vertex lasts 1.
+	VertexWeight func(v *Vertex) float64
+
+	// EdgeWeight return the delay between the end of the tail of an edge
+	// and the start of its head. If nil, edges add no delay. Use the
+	// EdgeWeight method of the graph for the Weight of each edge.
+	EdgeWeight func(tail *Vertex, head *Vertex) float64
+}
+
+// CriticalPath is the result of the critical path method on a graph, as
+// computed by DAG.CriticalPath.
+type CriticalPath struct {
+	// Path is the longest weighted path of the graph, from a source vertex
+	// to a sink vertex. Its vertices have no slack.
+	Path []*Vertex
+
+	// Cost is the total weight of Path, which is the earliest time all the
+	// vertices can be finished.
+	Cost float64
+
+	// Schedule holds the schedule of every vertex, in topological order.
+	Schedule []*VertexSchedule
+
+	index map[*Vertex]*VertexSchedule
+}
+
+// VertexSchedule is the schedule of a vertex given by the critical path
+// method. Times start at zero when the first vertices start.
+type VertexSchedule struct {
+	Vertex *Vertex
+
+	// EarliestStart and EarliestFinish are the earliest times the vertex
+	// can start and finish, once all its parents finished.
+	EarliestStart  float64
+	EarliestFinish float64
+
+	// LatestStart and LatestFinish are the latest times the vertex can
+	// start and finish without delaying the whole graph.
+	LatestStart  float64
+	LatestFinish float64
+
+	// Slack, or float, is how much the vertex can be delayed without
+	// delaying the whole graph. It is zero on the critical path.
+	Slack float64
+}
+
+// VertexSchedule return the schedule of a vertex, or nil if it doesn't
+// belong to the graph.
+func (c *CriticalPath) VertexSchedule(vertex *Vertex) *VertexSchedule {
+	return c.index[vertex]
+}
+
+// CriticalPath computes the critical path of the graph, following the
+// critical path method (CPM) used by PERT charts.
+//
+// Each vertex is seen as a job lasting its weight, which can only start
+// once all its parents finished, plus the weight of the edge from each of
+// them. A forward pass computes the earliest start and finish of every
+// vertex, and a backward pass the latest ones, so the slack of each vertex
+// is how much it can be delayed. The critical path is the longest weighted
+// path, whose vertices have no slack. Ties are broken by insertion order.
+//
+// Weights must not be negative. If opts is nil the defaults are used. It
+// returns a *CycleError if the graph is not acyclic.
+func (d *DAG) CriticalPath(opts *CriticalPathOptions) (*CriticalPath, error) {
+	if opts == nil {
+		opts = &CriticalPathOptions{}
+	}
+	vertexWeight := opts.VertexWeight
+	switch {
+	case vertexWeight == nil && opts.EdgeWeight == nil:
+		vertexWeight = func(v *Vertex) float64 { return 1 }
+	case vertexWeight == nil:
+		vertexWeight = func(v *Vertex) float64 { return 0 }
+	}
+	edgeWeight := opts.EdgeWeight
+	if edgeWeight == nil {
+		edgeWeight = func(tail *Vertex, head *Vertex) float64 { return 0 }
+	}
+
+	sorted, err := d.TopologicalSortStable()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CriticalPath{
+		Schedule: make([]*VertexSchedule, len(sorted)),
+		index:    make(map[*Vertex]*VertexSchedule, len(sorted)),
+	}
+
+	// Forward pass, remembering the parent each vertex waits for.
+	critical := make(map[*Vertex]*Vertex, len(sorted))
+	var last *VertexSchedule
+	for i, vertex := range sorted {
+		weight := vertexWeight(vertex)
+		if weight < 0 || math.IsNaN(weight) {
+			return nil, fmt.Errorf("invalid weight %v for vertex %s", weight, vertex.ID)
+		}
+
+		schedule := &VertexSchedule{Vertex: vertex}
+		for _, parent := range vertex.Parents.Values() {
+			parent := parent.(*Vertex)
+			parentSchedule, found := result.index[parent]
+			if !found {
+				continue
+			}
+
+			delay := edgeWeight(parent, vertex)
+			if delay < 0 || math.IsNaN(delay) {
+				return nil, fmt.Errorf("invalid weight %v for edge (%s,%s)", delay, parent.ID, vertex.ID)
+			}
+			if start := parentSchedule.EarliestFinish + delay; start > schedule.EarliestStart || critical[vertex] == nil {
+				schedule.EarliestStart = start
+				critical[vertex] = parent
+			}
+		}
+		schedule.EarliestFinish = schedule.EarliestStart + weight
+
+		result.Schedule[i] = schedule
+		result.index[vertex] = schedule
+
+		// The path ends at a sink: with weights of zero, a vertex finishing
+		// last may still have children finishing at the same time.
+		if vertex.OutDegree() == 0 && (last == nil || schedule.EarliestFinish > last.EarliestFinish) {
+			last = schedule
+		}
+	}
+	if last == nil {
+		return result, nil
+	}
+	result.Cost = last.EarliestFinish
+
+	// Backward pass.
+	for i := len(sorted) - 1; i >= 0; i-- {
+		vertex := sorted[i]
+		schedule := result.Schedule[i]
+
+		schedule.LatestFinish = result.Cost
+		for _, child := range vertex.Children.Values() {
+			child := child.(*Vertex)
+			childSchedule, found := result.index[child]
+			if !found {
+				continue
+			}
+			if finish := childSchedule.LatestStart - edgeWeight(vertex, child); finish < schedule.LatestFinish {
+				schedule.LatestFinish = finish
+			}
+		}
+		schedule.LatestStart = schedule.LatestFinish - (schedule.EarliestFinish - schedule.EarliestStart)
+		schedule.Slack = schedule.LatestStart - schedule.EarliestStart
+	}
+
+	for vertex := last.Vertex; vertex != nil; vertex = critical[vertex] {
+		result.Path = append(result.Path, vertex)
+	}
+	for i, j := 0, len(result.Path)-1; i < j; i, j = i+1, j-1 {
+		result.Path[i], result.Path[j] = result.Path[j], result.Path[i]
+	}
+
+	return result, nil
+}
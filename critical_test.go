@@ -0,0 +1,188 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+func TestDAG_CriticalPath(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"start", "design", "build", "docs", "test", "ship"},
+		[][2]string{
+			{"start", "design"}, {"design", "build"}, {"design", "docs"},
+			{"build", "test"}, {"test", "ship"}, {"docs", "ship"},
+		},
+	)
+	durations := map[string]float64{"start": 0, "design": 2, "build": 5, "docs": 3, "test": 2, "ship": 1}
+
+	critical, err := dag1.CriticalPath(&dag.CriticalPathOptions{
+		VertexWeight: func(v *dag.Vertex) float64 {
+			return durations[v.ID]
+		},
+	})
+	if err != nil {
+		t.Fatalf("Can't compute critical path: %s", err)
+	}
+
+	expectedPath := []string{"start", "design", "build", "test", "ship"}
+	if !reflect.DeepEqual(vertexIDs(critical.Path), expectedPath) {
+		t.Fatalf("Expected critical path to be %v but got %v", expectedPath, vertexIDs(critical.Path))
+	}
+	if critical.Cost != 10 {
+		t.Fatalf("Expected critical path cost to be 10 but got %v", critical.Cost)
+	}
+
+	expectedSchedules := map[string]dag.VertexSchedule{
+		"start":  {EarliestStart: 0, EarliestFinish: 0, LatestStart: 0, LatestFinish: 0, Slack: 0},
+		"design": {EarliestStart: 0, EarliestFinish: 2, LatestStart: 0, LatestFinish: 2, Slack: 0},
+		"build":  {EarliestStart: 2, EarliestFinish: 7, LatestStart: 2, LatestFinish: 7, Slack: 0},
+		"docs":   {EarliestStart: 2, EarliestFinish: 5, LatestStart: 6, LatestFinish: 9, Slack: 4},
+		"test":   {EarliestStart: 7, EarliestFinish: 9, LatestStart: 7, LatestFinish: 9, Slack: 0},
+		"ship":   {EarliestStart: 9, EarliestFinish: 10, LatestStart: 9, LatestFinish: 10, Slack: 0},
+	}
+	for id, expected := range expectedSchedules {
+		expected.Vertex = vertices[id]
+		schedule := critical.VertexSchedule(vertices[id])
+		if schedule == nil || *schedule != expected {
+			t.Fatalf("Expected vertex %s schedule to be %+v but got %+v", id, expected, schedule)
+		}
+	}
+
+	if len(critical.Schedule) != dag1.Order() || critical.Schedule[0].Vertex != vertices["start"] {
+		t.Fatalf("Expected a schedule for each vertex in topological order but got %d", len(critical.Schedule))
+	}
+	if critical.VertexSchedule(dag.NewVertex("start", nil)) != nil {
+		t.Fatalf("Expected no schedule for a vertex out of the graph")
+	}
+}
+
+func TestDAG_CriticalPath_EdgeWeights(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}},
+	)
+	delays := map[[2]string]float64{{"a", "b"}: 1, {"a", "c"}: 4, {"b", "d"}: 1, {"c", "d"}: 0.5}
+
+	critical, err := dag1.CriticalPath(&dag.CriticalPathOptions{
+		EdgeWeight: func(tail *dag.Vertex, head *dag.Vertex) float64 {
+			return delays[[2]string{tail.ID, head.ID}]
+		},
+	})
+	if err != nil {
+		t.Fatalf("Can't compute critical path: %s", err)
+	}
+
+	expectedPath := []string{"a", "c", "d"}
+	if !reflect.DeepEqual(vertexIDs(critical.Path), expectedPath) {
+		t.Fatalf("Expected critical path to be %v but got %v", expectedPath, vertexIDs(critical.Path))
+	}
+	if critical.Cost != 4.5 {
+		t.Fatalf("Expected critical path cost to be 4.5 but got %v", critical.Cost)
+	}
+	if slack := critical.VertexSchedule(vertices["b"]).Slack; slack != 2.5 {
+		t.Fatalf("Expected vertex b slack to be 2.5 but got %v", slack)
+	}
+}
+
+func TestDAG_CriticalPath_ZeroWeights(t *testing.T) {
+	dag1, _ := newTestDAG(t,
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"b", "c"}},
+	)
+	weights := map[string]float64{"a": 2, "b": 0, "c": 0, "d": 1}
+
+	critical, err := dag1.CriticalPath(&dag.CriticalPathOptions{
+		VertexWeight: func(v *dag.Vertex) float64 {
+			return weights[v.ID]
+		},
+	})
+	if err != nil {
+		t.Fatalf("Can't compute critical path: %s", err)
+	}
+
+	// The path goes on to the sink through the vertices lasting 0.
+	expectedPath := []string{"a", "b", "c"}
+	if !reflect.DeepEqual(vertexIDs(critical.Path), expectedPath) {
+		t.Fatalf("Expected critical path to be %v but got %v", expectedPath, vertexIDs(critical.Path))
+	}
+	if critical.Cost != 2 {
+		t.Fatalf("Expected critical path cost to be 2 but got %v", critical.Cost)
+	}
+}
+
+func TestDAG_CriticalPath_Defaults(t *testing.T) {
+	dag1, _ := newTestDAG(t,
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"d", "c"}, {"e", "c"}},
+	)
+
+	critical, err := dag1.CriticalPath(nil)
+	if err != nil {
+		t.Fatalf("Can't compute critical path: %s", err)
+	}
+
+	expectedPath := []string{"a", "b", "c"}
+	if !reflect.DeepEqual(vertexIDs(critical.Path), expectedPath) {
+		t.Fatalf("Expected critical path to be %v but got %v", expectedPath, vertexIDs(critical.Path))
+	}
+	if critical.Cost != 3 {
+		t.Fatalf("Expected critical path cost to be 3 but got %v", critical.Cost)
+	}
+
+	critical, err = dag.NewDAG().CriticalPath(nil)
+	if err != nil {
+		t.Fatalf("Can't compute critical path: %s", err)
+	}
+	if len(critical.Path) != 0 || critical.Cost != 0 {
+		t.Fatalf("Expected empty critical path but got %v with cost %v", vertexIDs(critical.Path), critical.Cost)
+	}
+}
+
+func TestDAG_CriticalPath_Errors(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"a", "b"},
+		[][2]string{{"a", "b"}},
+	)
+
+	_, err := dag1.CriticalPath(&dag.CriticalPathOptions{
+		VertexWeight: func(v *dag.Vertex) float64 { return -1 },
+	})
+	if err == nil || err.Error() != "invalid weight -1 for vertex a" {
+		t.Fatalf("Expected error message to be %q but got %v", "invalid weight -1 for vertex a", err)
+	}
+
+	_, err = dag1.CriticalPath(&dag.CriticalPathOptions{
+		EdgeWeight: func(tail *dag.Vertex, head *dag.Vertex) float64 { return -2 },
+	})
+	if err == nil || err.Error() != "invalid weight -2 for edge (a,b)" {
+		t.Fatalf("Expected error message to be %q but got %v", "invalid weight -2 for edge (a,b)", err)
+	}
+
+	vertices["b"].Children.Add(vertices["a"])
+	vertices["a"].Parents.Add(vertices["b"])
+
+	_, err = dag1.CriticalPath(nil)
+	var cycleErr *dag.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected error to be a *dag.CycleError but got %v", err)
+	}
+}
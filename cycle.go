@@ -0,0 +1,97 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag
+
+import (
+	"strings"
+)
+
+// CycleError is returned when an operation would make the graph cyclic, or
+// finds that it already is.
+//
+// Path holds the vertices of the offending cycle in edge order, starting and
+// ending at the same vertex, for example a -> b -> c -> a.
+type CycleError struct {
+	Path []*Vertex
+}
+
+// Error implements the error interface.
+func (e *CycleError) Error() string {
+	ids := make([]string, len(e.Path))
+	for i, vertex := range e.Path {
+		ids[i] = vertex.ID
+	}
+
+	return "cycle detected: " + strings.Join(ids, " -> ")
+}
+
+// newEdgeCycleError returns a CycleError if adding the edge (tailVertex,
+// headVertex) would close a directed cycle, or nil otherwise.
+func newEdgeCycleError(tailVertex *Vertex, headVertex *Vertex) *CycleError {
+	if tailVertex == headVertex {
+		return &CycleError{Path: []*Vertex{tailVertex, tailVertex}}
+	}
+
+	path := findPath(headVertex, tailVertex)
+	if path == nil {
+		return nil
+	}
+
+	return &CycleError{Path: append([]*Vertex{tailVertex}, path...)}
+}
+
+// findPath returns the first path found from one vertex to another following
+// the children of each vertex, or nil if there is none.
+func findPath(from *Vertex, to *Vertex) []*Vertex {
+	visited := make(map[*Vertex]bool)
+
+	// path is built backwards while the recursion unwinds.
+	var path []*Vertex
+
+	var visit func(vertex *Vertex) bool
+	visit = func(vertex *Vertex) bool {
+		if vertex == to {
+			path = append(path, vertex)
+			return true
+		}
+		visited[vertex] = true
+
+		for _, child := range vertex.Children.Values() {
+			child := child.(*Vertex)
+			if visited[child] {
+				continue
+			}
+			if visit(child) {
+				path = append(path, vertex)
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if !visit(from) {
+		return nil
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path
+}
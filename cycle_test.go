@@ -0,0 +1,130 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag_test
+
+import (
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+func TestDAG_AddEdge_FailsCycle(t *testing.T) {
+	dag1 := dag.NewDAG()
+
+	vertexA := dag.NewVertex("a", nil)
+	vertexB := dag.NewVertex("b", nil)
+	vertexC := dag.NewVertex("c", nil)
+
+	for _, vertex := range []*dag.Vertex{vertexA, vertexB, vertexC} {
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+	}
+
+	err := dag1.AddEdge(vertexA, vertexB)
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+	err = dag1.AddEdge(vertexB, vertexC)
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+
+	err = dag1.AddEdge(vertexC, vertexA)
+	if err == nil {
+		t.Fatalf("Edge closes a cycle, AddEdge should fail but it doesn't")
+	}
+
+	cycleErr, ok := err.(*dag.CycleError)
+	if !ok {
+		t.Fatalf("Expected error to be a *dag.CycleError but got %T", err)
+	}
+	expected := []*dag.Vertex{vertexC, vertexA, vertexB, vertexC}
+	if len(cycleErr.Path) != len(expected) {
+		t.Fatalf("Expected cycle path length to be %d but got %d", len(expected), len(cycleErr.Path))
+	}
+	for i := range expected {
+		if cycleErr.Path[i] != expected[i] {
+			t.Fatalf("Expected cycle path vertex %d to be %q but got %q", i, expected[i].ID, cycleErr.Path[i].ID)
+		}
+	}
+
+	expectedMsg := "cycle detected: c -> a -> b -> c"
+	if err.Error() != expectedMsg {
+		t.Fatalf("Expected error message to be %q but got %q", expectedMsg, err.Error())
+	}
+
+	if dag1.Size() != 2 {
+		t.Fatalf("Dag expected to have 2 edges but got %d", dag1.Size())
+	}
+	if vertexA.Parents.Size() != 0 {
+		t.Fatalf("Vertex a expected to have 0 parents but got %d", vertexA.Parents.Size())
+	}
+}
+
+func TestDAG_AddEdge_FailsSelfLoop(t *testing.T) {
+	dag1 := dag.NewDAG()
+
+	vertex1 := dag.NewVertex("1", nil)
+
+	err := dag1.AddVertex(vertex1)
+	if err != nil {
+		t.Fatalf("Can't add vertex to DAG: %s", err)
+	}
+
+	err = dag1.AddEdge(vertex1, vertex1)
+	if err == nil {
+		t.Fatalf("Edge is a self loop, AddEdge should fail but it doesn't")
+	}
+
+	expectedMsg := "cycle detected: 1 -> 1"
+	if err.Error() != expectedMsg {
+		t.Fatalf("Expected error message to be %q but got %q", expectedMsg, err.Error())
+	}
+}
+
+func TestDAG_AddEdge_Diamond(t *testing.T) {
+	dag1 := dag.NewDAG()
+
+	vertex1 := dag.NewVertex("1", nil)
+	vertex2 := dag.NewVertex("2", nil)
+	vertex3 := dag.NewVertex("3", nil)
+	vertex4 := dag.NewVertex("4", nil)
+
+	for _, vertex := range []*dag.Vertex{vertex1, vertex2, vertex3, vertex4} {
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+	}
+
+	edges := [][2]*dag.Vertex{
+		{vertex1, vertex2},
+		{vertex1, vertex3},
+		{vertex2, vertex4},
+		{vertex3, vertex4},
+		{vertex1, vertex4},
+	}
+	for _, edge := range edges {
+		err := dag1.AddEdge(edge[0], edge[1])
+		if err != nil {
+			t.Fatalf("Can't add edge (%s,%s) to DAG: %s", edge[0].ID, edge[1].ID, err)
+		}
+	}
+}
@@ -28,22 +28,59 @@ import (
 type DAG struct {
 	mu       sync.Mutex
 	vertices orderedmap.OrderedMap
+
+	// edges holds the data of every edge.
+	edges map[edgeKey]*Edge
+
+	// labels is the index of vertex labels used by VerticesByLabel.
+	labels *labelIndex
+
+	// reach is the reachability index used by IsReachable, or nil if it
+	// isn't built yet.
+	reach *reachIndex
 }
 
 // NewDAG creates a new Directed Acyclic Graph or DAG.
 func NewDAG() *DAG {
 	d := &DAG{
 		vertices: *orderedmap.NewOrderedMap(),
+		edges:    make(map[edgeKey]*Edge),
+		labels:   newLabelIndex(),
 	}
 
 	return d
 }
 
-// AddVertex adds a vertex to the graph.
+// AddVertex adds a vertex to the graph, or replaces the one with the same
+// ID. Its Labels are indexed for VerticesByLabel.
+//
+// It fails if a label key or value is invalid, as described in
+// SetVertexLabel.
 func (d *DAG) AddVertex(v *Vertex) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
+	err := checkLabels(v.Labels)
+	if err != nil {
+		return fmt.Errorf("vertex %s: %w", v.ID, err)
+	}
+
+	if d.reach != nil {
+		if existing, found := d.vertices.Get(v.ID); found && existing != v {
+			// The vertex replaced keeps its edges, so the index would
+			// need to be rebuilt anyway.
+			d.reach = nil
+		} else if !found {
+			d.reach.addVertex(v)
+		}
+	}
+
+	if existing, found := d.vertices.Get(v.ID); found {
+		d.labels.replace(existing.(*Vertex), v)
+	} else {
+		d.labels.add(v)
+	}
+
 	d.vertices.Put(v.ID, v)
 
 	return nil
@@ -52,60 +89,86 @@ func (d *DAG) AddVertex(v *Vertex) error {
 // DeleteVertex deletes a vertex and all the edges referencing it from the
 // graph.
 func (d *DAG) DeleteVertex(vertex *Vertex) error {
-	existsVertex := false
-
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
 	// Check if vertices exists.
-	for _, v := range d.vertices.Values() {
-		if v == vertex {
-			existsVertex = true
+	if !d.containsVertex(vertex) {
+		return fmt.Errorf("Vertex with ID %v not found", vertex.ID)
+	}
+
+	// Remove the edges referencing the vertex in both directions.
+	for _, parent := range vertex.Parents.Values() {
+		parent.(*Vertex).Children.Remove(vertex)
+		vertex.Parents.Remove(parent)
+		delete(d.edges, edgeKey{parent.(*Vertex), vertex})
+		if d.reach != nil {
+			d.reach.removeChild(parent.(*Vertex), vertex)
 		}
 	}
-	if !existsVertex {
-		return fmt.Errorf("Vertex with ID %v not found", vertex.ID)
+	for _, child := range vertex.Children.Values() {
+		child.(*Vertex).Parents.Remove(vertex)
+		vertex.Children.Remove(child)
+		delete(d.edges, edgeKey{vertex, child.(*Vertex)})
 	}
 
 	d.vertices.Remove(vertex.ID)
+	d.labels.remove(vertex)
+
+	if d.reach != nil {
+		delete(d.reach.labels, vertex)
+		d.reach.deletions++
+	}
 
 	return nil
 }
 
 // AddEdge adds a directed edge between two existing vertices to the graph.
+//
+// The edge has no label, a weight of 1 and no attributes. It returns a
+// *CycleError if the new edge would close a directed cycle.
 func (d *DAG) AddEdge(tailVertex *Vertex, headVertex *Vertex) error {
-	tailExists := false
-	headExists := false
+	return d.addEdge(newEdge(tailVertex, headVertex))
+}
+
+// addEdge adds an edge between two existing vertices to the graph.
+func (d *DAG) addEdge(edge *Edge) error {
+	tailVertex := edge.Tail
+	headVertex := edge.Head
 
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
 	// Check if vertices exists.
-	for _, vertex := range d.vertices.Values() {
-		if vertex == tailVertex {
-			tailExists = true
-		}
-		if vertex == headVertex {
-			headExists = true
-		}
-	}
-	if !tailExists {
+	if !d.containsVertex(tailVertex) {
 		return fmt.Errorf("Vertex with ID %v not found", tailVertex.ID)
 	}
-	if !headExists {
+	if !d.containsVertex(headVertex) {
 		return fmt.Errorf("Vertex with ID %v not found", headVertex.ID)
 	}
 
 	// Check if edge already exists.
-	for _, childVertex := range tailVertex.Children.Values() {
-		if childVertex == headVertex {
-			return fmt.Errorf("Edge (%v,%v) already exists", tailVertex.ID, headVertex.ID)
+	if tailVertex.Children.Contains(headVertex) {
+		return fmt.Errorf("Edge (%v,%v) already exists", tailVertex.ID, headVertex.ID)
+	}
+
+	// Check the edge doesn't make the graph cyclic. There is no need to
+	// look for a path from the head to the tail if the reachability index
+	// rules it out.
+	if d.reach == nil || d.reach.isReachable(headVertex, tailVertex) {
+		if err := newEdgeCycleError(tailVertex, headVertex); err != nil {
+			return err
 		}
 	}
 
 	// Add edge.
 	tailVertex.Children.Add(headVertex)
 	headVertex.Parents.Add(tailVertex)
+	d.edges[edgeKey{tailVertex, headVertex}] = edge
+
+	if d.reach != nil {
+		d.reach.addEdge(tailVertex, headVertex)
+	}
 
 	return nil
 }
@@ -113,10 +176,30 @@ func (d *DAG) AddEdge(tailVertex *Vertex, headVertex *Vertex) error {
 // DeleteEdge deletes a directed edge between two existing vertices from the
 // graph.
 func (d *DAG) DeleteEdge(tailVertex *Vertex, headVertex *Vertex) error {
-	for _, childVertex := range tailVertex.Children.Values() {
-		if childVertex == headVertex {
-			tailVertex.Children.Remove(childVertex)
-		}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Check if vertices exists.
+	if !d.containsVertex(tailVertex) {
+		return fmt.Errorf("Vertex with ID %v not found", tailVertex.ID)
+	}
+	if !d.containsVertex(headVertex) {
+		return fmt.Errorf("Vertex with ID %v not found", headVertex.ID)
+	}
+
+	// Check if edge exists.
+	if !tailVertex.Children.Contains(headVertex) {
+		return fmt.Errorf("Edge (%v,%v) not found", tailVertex.ID, headVertex.ID)
+	}
+
+	// Delete edge.
+	tailVertex.Children.Remove(headVertex)
+	headVertex.Parents.Remove(tailVertex)
+	delete(d.edges, edgeKey{tailVertex, headVertex})
+
+	if d.reach != nil {
+		d.reach.removeChild(tailVertex, headVertex)
+		d.reach.deletions++
 	}
 
 	return nil
@@ -136,6 +219,18 @@ func (d *DAG) GetVertex(id interface{}) (*Vertex, error) {
 	return vertex, nil
 }
 
+// Vertices return the vertices of the graph in insertion order.
+func (d *DAG) Vertices() []*Vertex {
+	values := d.vertices.Values()
+
+	vertices := make([]*Vertex, len(values))
+	for i, vertex := range values {
+		vertices[i] = vertex.(*Vertex)
+	}
+
+	return vertices
+}
+
 // Order return the number of vertices in the graph.
 func (d *DAG) Order() int {
 	numVertices := d.vertices.Size()
@@ -211,6 +306,14 @@ func (d *DAG) Predecessors(vertex *Vertex) ([]*Vertex, error) {
 	return predecessors, nil
 }
 
+// containsVertex return if the given vertex, and not just another one with
+// the same ID, belongs to the graph.
+func (d *DAG) containsVertex(vertex *Vertex) bool {
+	v, found := d.vertices.Get(vertex.ID)
+
+	return found && v.(*Vertex) == vertex
+}
+
 // String implements stringer interface.
 //
 // Prints an string representation of this instance.
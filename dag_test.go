@@ -18,6 +18,7 @@
 package dag_test
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/goombaio/dag"
@@ -185,6 +186,89 @@ func TestDAG_DeleteEdge(t *testing.T) {
 	}
 }
 
+func TestDAG_DeleteVertex_DeletesEdges(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"1", "2", "3"},
+		[][2]string{{"1", "2"}, {"2", "3"}, {"1", "3"}},
+	)
+
+	err := dag1.DeleteVertex(vertices["2"])
+	if err != nil {
+		t.Fatalf("Can't delete vertex from DAG: %s", err)
+	}
+
+	size := dag1.Size()
+	if size != 1 {
+		t.Fatalf("Dag expected to have 1 edge but got %d", size)
+	}
+	if vertices["1"].Children.Contains(vertices["2"]) {
+		t.Fatalf("Vertex 1 children expected to not contain deleted vertex 2")
+	}
+	if vertices["3"].Parents.Contains(vertices["2"]) {
+		t.Fatalf("Vertex 3 parents expected to not contain deleted vertex 2")
+	}
+	if vertices["2"].Degree() != 0 {
+		t.Fatalf("Deleted vertex 2 Degree expected to be 0 but got %d", vertices["2"].Degree())
+	}
+
+	predecessors, err := dag1.Predecessors(vertices["3"])
+	if err != nil {
+		t.Fatalf("Can't get %s predecessors: %s", vertices["3"], err)
+	}
+	if len(predecessors) != 1 {
+		t.Fatalf("Expected to have 1 predecessor but got %d", len(predecessors))
+	}
+
+	err = dag1.Integrity()
+	if err != nil {
+		t.Fatalf("DAG expected to be consistent: %s", err)
+	}
+}
+
+func TestDAG_DeleteEdge_DeletesParent(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"1", "2"},
+		[][2]string{{"1", "2"}},
+	)
+
+	err := dag1.DeleteEdge(vertices["1"], vertices["2"])
+	if err != nil {
+		t.Fatalf("Can't delete edge from DAG: %s", err)
+	}
+
+	if vertices["2"].Parents.Size() != 0 {
+		t.Fatalf("Vertex 2 Parents expected to be 0 but got %d", vertices["2"].Parents.Size())
+	}
+
+	sourceVertices := dag1.SourceVertices()
+	if len(sourceVertices) != 2 {
+		t.Fatalf("Expected to have 2 Source vertices but got %d", len(sourceVertices))
+	}
+}
+
+func TestDAG_DeleteEdge_Fails(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"1", "2"},
+		nil,
+	)
+	vertex3 := dag.NewVertex("3", nil)
+
+	err := dag1.DeleteEdge(vertices["1"], vertices["2"])
+	if err == nil {
+		t.Fatalf("Edge don't exist, DeleteEdge should fail but it doesn't")
+	}
+
+	err = dag1.DeleteEdge(vertices["1"], vertex3)
+	if err == nil {
+		t.Fatalf("Vertex don't exist, DeleteEdge should fail but it doesn't")
+	}
+
+	err = dag1.DeleteEdge(vertex3, vertices["1"])
+	if err == nil {
+		t.Fatalf("Vertex don't exist, DeleteEdge should fail but it doesn't")
+	}
+}
+
 func TestDAG_GetVertex(t *testing.T) {
 	dag1 := dag.NewDAG()
 
@@ -209,7 +293,20 @@ func TestDAG_GetVertex(t *testing.T) {
 		t.Fatalf("Expected value1 to be %q but got %v.", expected1, v1.Value)
 	}
 	if v1.Value != expected1 {
-		t.Fatalf("Expected value2 to be %q but got %v.", expected2, v2.Value)
+		t.Fatalf("Expected value2 to be %d but got %v.", expected2, v2.Value)
+	}
+}
+
+func TestDAG_Vertices(t *testing.T) {
+	dag1, _ := newTestDAG(t,
+		[]string{"3", "1", "2"},
+		[][2]string{{"1", "3"}},
+	)
+
+	ids := vertexIDs(dag1.Vertices())
+	expected := []string{"3", "1", "2"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("Expected vertices to be %v but got %v", expected, ids)
 	}
 }
 
@@ -495,3 +592,38 @@ func TestDAG_Predecessors_VertexNotFound(t *testing.T) {
 		t.Fatalf("Got %d predecessors for vertex %s, but expected to fail", len(predecessors), vertex3.ID)
 	}
 }
+
+// newTestDAG builds a DAG with a vertex for each id, added in order, and the
+// given edges as pairs of tail and head ids.
+func newTestDAG(t *testing.T, ids []string, edges [][2]string) (*dag.DAG, map[string]*dag.Vertex) {
+	dag1 := dag.NewDAG()
+	vertices := make(map[string]*dag.Vertex, len(ids))
+
+	for _, id := range ids {
+		vertex := dag.NewVertex(id, nil)
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+		vertices[id] = vertex
+	}
+
+	for _, edge := range edges {
+		err := dag1.AddEdge(vertices[edge[0]], vertices[edge[1]])
+		if err != nil {
+			t.Fatalf("Can't add edge (%s,%s) to DAG: %s", edge[0], edge[1], err)
+		}
+	}
+
+	return dag1, vertices
+}
+
+// vertexIDs return the IDs of the given vertices.
+func vertexIDs(vertices []*dag.Vertex) []string {
+	ids := make([]string, len(vertices))
+	for i, vertex := range vertices {
+		ids[i] = vertex.ID
+	}
+
+	return ids
+}
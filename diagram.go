@@ -0,0 +1,241 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DiagramOptions configures the output of WriteMermaid and WritePlantUML.
+type DiagramOptions struct {
+	// Direction is the direction of the flow, "TD" (or "TB") for top down
+	// or "LR" for left to right. Mermaid also supports "BT" and "RL". It
+	// defaults to top down.
+	Direction string
+
+	// Label return the label of a vertex. If nil, vertices are labelled
+	// with their ID. Use ValueLabel to label them with their Value.
+	Label func(v *Vertex) string
+
+	// Style return the style of a vertex, written as is, or an empty string
+	// for the default style. For Mermaid it is a list of style properties
+	// such as "fill:#f96,stroke:#333", and for PlantUML a color
+	// specification such as "#pink;line:red".
+	Style func(v *Vertex) string
+
+	// Group return the name of the group, a Mermaid subgraph or a PlantUML
+	// package, a vertex is drawn in, or an empty string to draw it outside
+	// any group.
+	Group func(v *Vertex) string
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart, with a node for each
+// vertex in insertion order and a link for each child of a vertex.
+//
+// Nodes are named after their position in the graph, n0, n1 and so on, so
+// any vertex ID is safe, and labelled with the vertex ID or Label. Links
+// are labelled with the edge Label, if any.
+//
+// If opts is nil the defaults are used.
+func (d *DAG) WriteMermaid(w io.Writer, opts *DiagramOptions) error {
+	if opts == nil {
+		opts = &DiagramOptions{}
+	}
+	direction := opts.Direction
+	switch direction {
+	case "":
+		direction = "TD"
+	case "TD", "TB", "BT", "LR", "RL":
+	default:
+		return fmt.Errorf("invalid direction %q", opts.Direction)
+	}
+
+	vertices := d.Vertices()
+	names := diagramNames(vertices)
+
+	writeNode := func(b *bufio.Writer, indent string, vertex *Vertex) {
+		fmt.Fprintf(b, "%s%s[%s]\n", indent, names[vertex], mermaidQuote(diagramLabel(opts, vertex)))
+	}
+
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintf(b, "flowchart %s\n", direction)
+
+	groups, grouped, ungrouped := groupVertices(vertices, opts.Group)
+	for i, group := range groups {
+		fmt.Fprintf(b, "    subgraph g%d [%s]\n", i, mermaidQuote(group))
+		for _, vertex := range grouped[group] {
+			writeNode(b, "        ", vertex)
+		}
+		fmt.Fprintf(b, "    end\n")
+	}
+	for _, vertex := range ungrouped {
+		writeNode(b, "    ", vertex)
+	}
+
+	for _, edge := range d.Edges() {
+		if edge.Label != "" {
+			fmt.Fprintf(b, "    %s -->|%s| %s\n", names[edge.Tail], mermaidQuote(edge.Label), names[edge.Head])
+			continue
+		}
+		fmt.Fprintf(b, "    %s --> %s\n", names[edge.Tail], names[edge.Head])
+	}
+
+	if opts.Style != nil {
+		for _, vertex := range vertices {
+			if style := opts.Style(vertex); style != "" {
+				fmt.Fprintf(b, "    style %s %s\n", names[vertex], style)
+			}
+		}
+	}
+
+	return b.Flush()
+}
+
+// WritePlantUML writes the graph as a PlantUML diagram, with a rectangle for
+// each vertex in insertion order and an arrow for each child of a vertex.
+//
+// Rectangles are named after their position in the graph, n0, n1 and so
+// on, so any vertex ID is safe, and labelled with the vertex ID or Label.
+// Arrows are labelled with the edge Label, if any.
+//
+// If opts is nil the defaults are used.
+func (d *DAG) WritePlantUML(w io.Writer, opts *DiagramOptions) error {
+	if opts == nil {
+		opts = &DiagramOptions{}
+	}
+	switch opts.Direction {
+	case "", "TD", "TB", "LR":
+	default:
+		return fmt.Errorf("invalid direction %q", opts.Direction)
+	}
+
+	vertices := d.Vertices()
+	names := diagramNames(vertices)
+
+	writeNode := func(b *bufio.Writer, indent string, vertex *Vertex) {
+		fmt.Fprintf(b, "%srectangle %s as %s", indent, plantUMLQuote(diagramLabel(opts, vertex)), names[vertex])
+		if opts.Style != nil {
+			if style := opts.Style(vertex); style != "" {
+				fmt.Fprintf(b, " %s", style)
+			}
+		}
+		fmt.Fprintf(b, "\n")
+	}
+
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintf(b, "@startuml\n")
+	if opts.Direction == "LR" {
+		fmt.Fprintf(b, "left to right direction\n")
+	}
+
+	groups, grouped, ungrouped := groupVertices(vertices, opts.Group)
+	for _, group := range groups {
+		fmt.Fprintf(b, "package %s {\n", plantUMLQuote(group))
+		for _, vertex := range grouped[group] {
+			writeNode(b, "  ", vertex)
+		}
+		fmt.Fprintf(b, "}\n")
+	}
+	for _, vertex := range ungrouped {
+		writeNode(b, "", vertex)
+	}
+
+	for _, edge := range d.Edges() {
+		if edge.Label != "" {
+			fmt.Fprintf(b, "%s --> %s : %s\n", names[edge.Tail], names[edge.Head], plantUMLQuote(edge.Label))
+			continue
+		}
+		fmt.Fprintf(b, "%s --> %s\n", names[edge.Tail], names[edge.Head])
+	}
+
+	fmt.Fprintf(b, "@enduml\n")
+
+	return b.Flush()
+}
+
+// diagramNames return the node name of each vertex in a diagram, after its
+// position in the graph.
+func diagramNames(vertices []*Vertex) map[*Vertex]string {
+	names := make(map[*Vertex]string, len(vertices))
+	for i, vertex := range vertices {
+		names[vertex] = fmt.Sprintf("n%d", i)
+	}
+
+	return names
+}
+
+// diagramLabel return the label of a vertex in a diagram.
+func diagramLabel(opts *DiagramOptions, vertex *Vertex) string {
+	if opts.Label != nil {
+		return opts.Label(vertex)
+	}
+
+	return vertex.ID
+}
+
+// groupVertices splits vertices by the group returned by group, keeping
+// their order. Groups are returned in the order they first appear, and
+// vertices without a group apart.
+func groupVertices(vertices []*Vertex, group func(v *Vertex) string) ([]string, map[string][]*Vertex, []*Vertex) {
+	var groups []string
+	grouped := make(map[string][]*Vertex)
+	var ungrouped []*Vertex
+
+	for _, vertex := range vertices {
+		name := ""
+		if group != nil {
+			name = group(vertex)
+		}
+		if name == "" {
+			ungrouped = append(ungrouped, vertex)
+			continue
+		}
+		if _, found := grouped[name]; !found {
+			groups = append(groups, name)
+		}
+		grouped[name] = append(grouped[name], vertex)
+	}
+
+	return groups, grouped, ungrouped
+}
+
+// mermaidQuote return s as a Mermaid quoted string, using entity codes for
+// the characters with a special meaning.
+func mermaidQuote(s string) string {
+	s = strings.NewReplacer(
+		"#", "#35;",
+		`"`, "#quot;",
+		"<", "#lt;",
+		">", "#gt;",
+		"\n", "<br>",
+	).Replace(s)
+
+	return `"` + s + `"`
+}
+
+// plantUMLQuote return s as a PlantUML quoted string.
+func plantUMLQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, "&#34;", "\n", `\n`).Replace(s)
+
+	return `"` + s + `"`
+}
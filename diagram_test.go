@@ -0,0 +1,183 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+// newDiagramDAG return the graph used by the diagram tests.
+func newDiagramDAG(t *testing.T) (*dag.DAG, *dag.DiagramOptions) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"extract", "transform", "load", "end"},
+		[][2]string{{"extract", "transform"}, {"transform", "load"}, {"extract", "end"}},
+	)
+	vertices["extract"].Value = "Extract \"raw\" <data> #1"
+	vertices["transform"].Value = "Transform\nand clean"
+	vertices["load"].Value = `C:\load`
+	vertices["end"].Value = "End"
+
+	edge, _ := dag1.GetEdge(vertices["transform"], vertices["load"])
+	edge.Label = "clean \"raw\"\nrows"
+
+	opts := &dag.DiagramOptions{
+		Direction: "LR",
+		Label:     dag.ValueLabel,
+		Style: func(v *dag.Vertex) string {
+			if v == vertices["extract"] {
+				return "fill:#f96"
+			}
+			return ""
+		},
+		Group: func(v *dag.Vertex) string {
+			if v == vertices["end"] {
+				return ""
+			}
+			return "pipeline"
+		},
+	}
+
+	return dag1, opts
+}
+
+func TestDAG_WriteMermaid(t *testing.T) {
+	dag1, _ := newTestDAG(t,
+		[]string{"1", "2", "3"},
+		[][2]string{{"1", "2"}, {"1", "3"}, {"2", "3"}},
+	)
+
+	var buf bytes.Buffer
+	err := dag1.WriteMermaid(&buf, nil)
+	if err != nil {
+		t.Fatalf("Can't write Mermaid: %s", err)
+	}
+
+	expected := `flowchart TD
+    n0["1"]
+    n1["2"]
+    n2["3"]
+    n0 --> n1
+    n0 --> n2
+    n1 --> n2
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected Mermaid output to be %q but got %q", expected, buf.String())
+	}
+}
+
+func TestDAG_WriteMermaid_Options(t *testing.T) {
+	dag1, opts := newDiagramDAG(t)
+
+	var buf bytes.Buffer
+	err := dag1.WriteMermaid(&buf, opts)
+	if err != nil {
+		t.Fatalf("Can't write Mermaid: %s", err)
+	}
+
+	expected := `flowchart LR
+    subgraph g0 ["pipeline"]
+        n0["Extract #quot;raw#quot; #lt;data#gt; #35;1"]
+        n1["Transform<br>and clean"]
+        n2["C:\load"]
+    end
+    n3["End"]
+    n0 --> n1
+    n0 --> n3
+    n1 -->|"clean #quot;raw#quot;<br>rows"| n2
+    style n0 fill:#f96
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected Mermaid output to be %q but got %q", expected, buf.String())
+	}
+}
+
+func TestDAG_WritePlantUML(t *testing.T) {
+	dag1, _ := newTestDAG(t,
+		[]string{"1", "2", "3"},
+		[][2]string{{"1", "2"}, {"1", "3"}, {"2", "3"}},
+	)
+
+	var buf bytes.Buffer
+	err := dag1.WritePlantUML(&buf, nil)
+	if err != nil {
+		t.Fatalf("Can't write PlantUML: %s", err)
+	}
+
+	expected := `@startuml
+rectangle "1" as n0
+rectangle "2" as n1
+rectangle "3" as n2
+n0 --> n1
+n0 --> n2
+n1 --> n2
+@enduml
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected PlantUML output to be %q but got %q", expected, buf.String())
+	}
+}
+
+func TestDAG_WritePlantUML_Options(t *testing.T) {
+	dag1, opts := newDiagramDAG(t)
+	opts.Style = func(v *dag.Vertex) string {
+		if v.ID == "extract" {
+			return "#pink;line:red"
+		}
+		return ""
+	}
+
+	var buf bytes.Buffer
+	err := dag1.WritePlantUML(&buf, opts)
+	if err != nil {
+		t.Fatalf("Can't write PlantUML: %s", err)
+	}
+
+	expected := `@startuml
+left to right direction
+package "pipeline" {
+  rectangle "Extract &#34;raw&#34; <data> #1" as n0 #pink;line:red
+  rectangle "Transform\nand clean" as n1
+  rectangle "C:\\load" as n2
+}
+rectangle "End" as n3
+n0 --> n1
+n0 --> n3
+n1 --> n2 : "clean &#34;raw&#34;\nrows"
+@enduml
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected PlantUML output to be %q but got %q", expected, buf.String())
+	}
+}
+
+func TestDAG_WriteDiagram_InvalidDirection(t *testing.T) {
+	dag1 := dag.NewDAG()
+
+	var buf bytes.Buffer
+	err := dag1.WriteMermaid(&buf, &dag.DiagramOptions{Direction: "XY"})
+	if err == nil {
+		t.Fatalf("Direction is invalid, WriteMermaid should fail but it doesn't")
+	}
+
+	err = dag1.WritePlantUML(&buf, &dag.DiagramOptions{Direction: "RL"})
+	if err == nil {
+		t.Fatalf("Direction is not supported, WritePlantUML should fail but it doesn't")
+	}
+}
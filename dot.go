@@ -0,0 +1,226 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DOTOptions configures the Graphviz output of WriteDOT.
+type DOTOptions struct {
+	// Name is the name of the graph. It may be empty.
+	Name string
+
+	// RankDir is the direction of the layout, one of "TB", "LR", "BT" or
+	// "RL". If empty, Graphviz uses its default, top to bottom.
+	RankDir string
+
+	// Label return the label of a vertex. If nil, vertices are labelled
+	// with their ID. Use ValueLabel to label them with their Value.
+	Label func(v *Vertex) string
+
+	// Cluster return the name of the cluster a vertex is drawn in, or an
+	// empty string to draw it outside any cluster.
+	Cluster func(v *Vertex) string
+
+	// Highlight lists vertices to draw highlighted, such as a critical
+	// path. The edge from each of them to the next one in the list, if
+	// any, is highlighted too, so shortcuts between vertices of a path
+	// are not.
+	Highlight []*Vertex
+
+	// HighlightColor is the color of highlighted vertices and edges. It
+	// defaults to "red".
+	HighlightColor string
+}
+
+// ValueLabel labels a vertex with its Value, printed with fmt.Sprint.
+func ValueLabel(v *Vertex) string {
+	return fmt.Sprint(v.Value)
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language, with a node for
+// each vertex in insertion order and an edge for each child of a vertex.
+//
+// Edges are written with their Label and Weight, unless empty and 1, as the
+// label and weight attributes, and with their Attrs, printed with
+// fmt.Sprint, as attributes of their own, so ReadDOT reads them back. Attrs
+// can't hold a label or weight attribute.
+//
+// If opts is nil the defaults are used.
+func (d *DAG) WriteDOT(w io.Writer, opts *DOTOptions) error {
+	if opts == nil {
+		opts = &DOTOptions{}
+	}
+	switch opts.RankDir {
+	case "", "TB", "LR", "BT", "RL":
+	default:
+		return fmt.Errorf("invalid rank direction %q", opts.RankDir)
+	}
+
+	highlightColor := opts.HighlightColor
+	if highlightColor == "" {
+		highlightColor = "red"
+	}
+	highlighted := make(map[*Vertex]bool, len(opts.Highlight))
+	highlightedEdges := make(map[edgeKey]bool, len(opts.Highlight))
+	for i, vertex := range opts.Highlight {
+		highlighted[vertex] = true
+		if i > 0 {
+			highlightedEdges[edgeKey{opts.Highlight[i-1], vertex}] = true
+		}
+	}
+
+	vertices := d.Vertices()
+	edges := d.Edges()
+
+	for _, edge := range edges {
+		for _, key := range []string{"label", "weight"} {
+			if _, found := edge.Attrs[key]; found {
+				return fmt.Errorf("edge (%s,%s) has a %s attribute", edge.Tail.ID, edge.Head.ID, key)
+			}
+		}
+	}
+
+	clusters, clustered, unclustered := groupVertices(vertices, opts.Cluster)
+
+	writeNode := func(b *bufio.Writer, indent string, vertex *Vertex) {
+		var attrs []string
+		if opts.Label != nil {
+			attrs = append(attrs, "label="+dotQuote(opts.Label(vertex)))
+		}
+		if highlighted[vertex] {
+			attrs = append(attrs, "color="+dotQuote(highlightColor), "penwidth=2")
+		}
+
+		fmt.Fprintf(b, "%s%s", indent, dotQuote(vertex.ID))
+		if len(attrs) > 0 {
+			fmt.Fprintf(b, " [%s]", strings.Join(attrs, ", "))
+		}
+		fmt.Fprintf(b, ";\n")
+	}
+
+	b := bufio.NewWriter(w)
+
+	if opts.Name != "" {
+		fmt.Fprintf(b, "digraph %s {\n", dotQuote(opts.Name))
+	} else {
+		fmt.Fprintf(b, "digraph {\n")
+	}
+	if opts.RankDir != "" {
+		fmt.Fprintf(b, "\trankdir=%s;\n", opts.RankDir)
+	}
+
+	for i, cluster := range clusters {
+		fmt.Fprintf(b, "\tsubgraph cluster_%d {\n", i)
+		fmt.Fprintf(b, "\t\tlabel=%s;\n", dotQuote(cluster))
+		for _, vertex := range clustered[cluster] {
+			writeNode(b, "\t\t", vertex)
+		}
+		fmt.Fprintf(b, "\t}\n")
+	}
+	for _, vertex := range unclustered {
+		writeNode(b, "\t", vertex)
+	}
+
+	for _, edge := range edges {
+		var attrs []string
+		if edge.Label != "" {
+			attrs = append(attrs, "label="+dotQuote(edge.Label))
+		}
+		if edge.Weight != 1 {
+			// Infinities and NaN are not DOT numerals.
+			weight := strconv.FormatFloat(edge.Weight, 'g', -1, 64)
+			if math.IsInf(edge.Weight, 0) || math.IsNaN(edge.Weight) {
+				weight = dotQuote(weight)
+			}
+			attrs = append(attrs, "weight="+weight)
+		}
+		keys := make([]string, 0, len(edge.Attrs))
+		for key := range edge.Attrs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			attrs = append(attrs, dotName(key)+"="+dotQuote(fmt.Sprint(edge.Attrs[key])))
+		}
+		if highlightedEdges[edgeKey{edge.Tail, edge.Head}] {
+			attrs = append(attrs, "color="+dotQuote(highlightColor), "penwidth=2")
+		}
+
+		fmt.Fprintf(b, "\t%s -> %s", dotQuote(edge.Tail.ID), dotQuote(edge.Head.ID))
+		if len(attrs) > 0 {
+			fmt.Fprintf(b, " [%s]", strings.Join(attrs, ", "))
+		}
+		fmt.Fprintf(b, ";\n")
+	}
+
+	fmt.Fprintf(b, "}\n")
+
+	return b.Flush()
+}
+
+// dotName return s as a DOT ID, quoted unless it is a plain identifier
+// other than a keyword.
+func dotName(s string) string {
+	if s == "" {
+		return dotQuote(s)
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || i > 0 && '0' <= c && c <= '9') {
+			return dotQuote(s)
+		}
+	}
+	for _, keyword := range []string{"node", "edge", "graph", "digraph", "subgraph", "strict"} {
+		if strings.EqualFold(s, keyword) {
+			return dotQuote(s)
+		}
+	}
+
+	return s
+}
+
+// dotQuote return s as a DOT quoted string.
+//
+// Only double quotes are escaped, and newlines are kept as is, as the DOT
+// parser keeps every other character of a quoted string. A backslash
+// before a newline or the closing quote would escape it, so a line
+// continuation, which is dropped when reading, is put between them.
+func dotQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '"':
+			b.WriteString(`\"`)
+		case s[i] == '\\' && (i+1 == len(s) || s[i+1] == '\n'):
+			b.WriteString("\\\\\n")
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
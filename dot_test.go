@@ -0,0 +1,158 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+func TestDAG_WriteDOT(t *testing.T) {
+	dag1, _ := newTestDAG(t,
+		[]string{"1", "2", "3"},
+		[][2]string{{"1", "2"}, {"1", "3"}, {"2", "3"}},
+	)
+
+	var buf bytes.Buffer
+	err := dag1.WriteDOT(&buf, nil)
+	if err != nil {
+		t.Fatalf("Can't write DOT: %s", err)
+	}
+
+	expected := `digraph {
+	"1";
+	"2";
+	"3";
+	"1" -> "2";
+	"1" -> "3";
+	"2" -> "3";
+}
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected DOT output to be %q but got %q", expected, buf.String())
+	}
+}
+
+func TestDAG_WriteDOT_Options(t *testing.T) {
+	dag1 := dag.NewDAG()
+
+	extract := dag.NewVertex("extract", "Extract \"raw\" data")
+	transform := dag.NewVertex("transform", "Transform")
+	load := dag.NewVertex("load", "Load")
+	report := dag.NewVertex("report", "Report")
+
+	for _, vertex := range []*dag.Vertex{extract, transform, load, report} {
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+	}
+	edges := []struct {
+		tail  *dag.Vertex
+		head  *dag.Vertex
+		label string
+	}{
+		{extract, transform, "raw rows"},
+		{transform, load, "clean \"rows\""},
+		{extract, report, ""},
+	}
+	for _, edge := range edges {
+		err := dag1.AddEdgeWithAttrs(edge.tail, edge.head, edge.label, 1, nil)
+		if err != nil {
+			t.Fatalf("Can't add edge to DAG: %s", err)
+		}
+	}
+
+	opts := &dag.DOTOptions{
+		Name:    "etl",
+		RankDir: "LR",
+		Label:   dag.ValueLabel,
+		Cluster: func(v *dag.Vertex) string {
+			if v == report {
+				return ""
+			}
+			return "pipeline"
+		},
+		Highlight: []*dag.Vertex{extract, transform},
+	}
+
+	var buf bytes.Buffer
+	err := dag1.WriteDOT(&buf, opts)
+	if err != nil {
+		t.Fatalf("Can't write DOT: %s", err)
+	}
+
+	expected := `digraph "etl" {
+	rankdir=LR;
+	subgraph cluster_0 {
+		label="pipeline";
+		"extract" [label="Extract \"raw\" data", color="red", penwidth=2];
+		"transform" [label="Transform", color="red", penwidth=2];
+		"load" [label="Load"];
+	}
+	"report" [label="Report"];
+	"extract" -> "transform" [label="raw rows", color="red", penwidth=2];
+	"extract" -> "report";
+	"transform" -> "load" [label="clean \"rows\""];
+}
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected DOT output to be %q but got %q", expected, buf.String())
+	}
+}
+
+func TestDAG_WriteDOT_HighlightPath(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"a", "b", "c"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"a", "c"}},
+	)
+
+	var buf bytes.Buffer
+	err := dag1.WriteDOT(&buf, &dag.DOTOptions{
+		Highlight:      []*dag.Vertex{vertices["a"], vertices["b"], vertices["c"]},
+		HighlightColor: "blue",
+	})
+	if err != nil {
+		t.Fatalf("Can't write DOT: %s", err)
+	}
+
+	// The shortcut from a to c is not part of the path.
+	expected := `digraph {
+	"a" [color="blue", penwidth=2];
+	"b" [color="blue", penwidth=2];
+	"c" [color="blue", penwidth=2];
+	"a" -> "b" [color="blue", penwidth=2];
+	"a" -> "c";
+	"b" -> "c" [color="blue", penwidth=2];
+}
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected DOT output to be %q but got %q", expected, buf.String())
+	}
+}
+
+func TestDAG_WriteDOT_InvalidRankDir(t *testing.T) {
+	dag1 := dag.NewDAG()
+
+	var buf bytes.Buffer
+	err := dag1.WriteDOT(&buf, &dag.DOTOptions{RankDir: "XY"})
+	if err == nil {
+		t.Fatalf("Rank direction is invalid, WriteDOT should fail but it doesn't")
+	}
+}
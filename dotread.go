@@ -0,0 +1,717 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// DOTAttributesKey is the vertex metadata key holding the attributes
+	// of a node read by ReadDOT, as a map[string]string.
+	DOTAttributesKey = "dot.attributes"
+
+	// DOTSubgraphKey is the vertex metadata key holding the ID of the named
+	// subgraph a node read by ReadDOT was first declared in, as a string.
+	DOTSubgraphKey = "dot.subgraph"
+)
+
+// ReadDOT builds a graph from its description in the Graphviz DOT language.
+//
+// It supports a reasonable subset of the language: a single digraph, node
+// statements with attributes, default node attributes, edge chains such as
+// a -> b -> c, and subgraphs, also as edge ends like a -> {b c}. Vertices are
+// added in order of first appearance with a nil Value. Node attributes are
+// kept in the vertex metadata under DOTAttributesKey and DOTSubgraphKey.
+// The label and weight edge attributes set the edge Label and Weight, and
+// the other ones are kept in the edge Attrs as strings. Graph
+// attributes, ports and repeated edges are ignored.
+//
+// Errors, including a *CycleError for an edge closing a cycle, are returned
+// as a *ParseError with the position of the problem.
+func ReadDOT(r io.Reader) (*DAG, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &dotParser{
+		lexer: &dotLexer{src: []rune(string(src)), line: 1, column: 1},
+		dag:   NewDAG(),
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	if err := p.parseGraph(); err != nil {
+		return nil, err
+	}
+
+	return p.dag, nil
+}
+
+// dotTokenKind is the kind of a DOT token.
+type dotTokenKind int
+
+const (
+	dotEOF dotTokenKind = iota
+	dotID
+	dotPunct
+	dotEdgeOp
+)
+
+// dotToken is a lexical token of the DOT language.
+type dotToken struct {
+	kind dotTokenKind
+
+	// text is the value of an ID, without quotes, the punctuation character
+	// or the edge operator.
+	text string
+
+	// quoted tells an ID was a quoted or HTML string, so it is never a
+	// keyword.
+	quoted bool
+
+	line   int
+	column int
+}
+
+// is return if the token is the given punctuation character.
+func (t dotToken) is(punct string) bool {
+	return t.kind == dotPunct && t.text == punct
+}
+
+// isKeyword return if the token is the given DOT keyword.
+func (t dotToken) isKeyword(keyword string) bool {
+	return t.kind == dotID && !t.quoted && strings.EqualFold(t.text, keyword)
+}
+
+// String implements stringer interface.
+func (t dotToken) String() string {
+	switch t.kind {
+	case dotEOF:
+		return "end of input"
+	case dotID:
+		return fmt.Sprintf("%q", t.text)
+	}
+
+	return t.text
+}
+
+// dotLexer splits DOT source into tokens.
+type dotLexer struct {
+	src    []rune
+	pos    int
+	line   int
+	column int
+}
+
+// peek return the rune at the given offset from the current position, or 0
+// past the end of the input.
+func (l *dotLexer) peek(offset int) rune {
+	if l.pos+offset >= len(l.src) {
+		return 0
+	}
+
+	return l.src[l.pos+offset]
+}
+
+// advance moves past the current rune.
+func (l *dotLexer) advance() rune {
+	r := l.src[l.pos]
+	l.pos++
+	if r == '\n' {
+		l.line++
+		l.column = 1
+	} else {
+		l.column++
+	}
+
+	return r
+}
+
+// errorf return a ParseError at the given position.
+func (l *dotLexer) errorf(line int, column int, format string, args ...interface{}) error {
+	return &ParseError{Line: line, Column: column, Err: fmt.Errorf(format, args...)}
+}
+
+// skipSpace skips white space, comments and preprocessor lines.
+func (l *dotLexer) skipSpace() error {
+	for l.pos < len(l.src) {
+		r := l.peek(0)
+		switch {
+		case unicode.IsSpace(r):
+			l.advance()
+		case r == '#' && l.column == 1:
+			for l.pos < len(l.src) && l.peek(0) != '\n' {
+				l.advance()
+			}
+		case r == '/' && l.peek(1) == '/':
+			for l.pos < len(l.src) && l.peek(0) != '\n' {
+				l.advance()
+			}
+		case r == '/' && l.peek(1) == '*':
+			line, column := l.line, l.column
+			l.advance()
+			l.advance()
+			for !(l.peek(0) == '*' && l.peek(1) == '/') {
+				if l.pos >= len(l.src) {
+					return l.errorf(line, column, "unterminated comment")
+				}
+				l.advance()
+			}
+			l.advance()
+			l.advance()
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// next return the next token.
+func (l *dotLexer) next() (dotToken, error) {
+	if err := l.skipSpace(); err != nil {
+		return dotToken{}, err
+	}
+
+	t := dotToken{line: l.line, column: l.column}
+	if l.pos >= len(l.src) {
+		t.kind = dotEOF
+		return t, nil
+	}
+
+	r := l.peek(0)
+	switch {
+	case strings.ContainsRune("{}[]=;,:", r):
+		t.kind = dotPunct
+		t.text = string(l.advance())
+	case r == '-' && (l.peek(1) == '>' || l.peek(1) == '-'):
+		t.kind = dotEdgeOp
+		t.text = string(l.advance()) + string(l.advance())
+	case r == '"':
+		text, err := l.quoted()
+		if err != nil {
+			return t, err
+		}
+		t.kind, t.text, t.quoted = dotID, text, true
+	case r == '<':
+		text, err := l.html()
+		if err != nil {
+			return t, err
+		}
+		t.kind, t.text, t.quoted = dotID, text, true
+	case r == '-' || r == '.' || unicode.IsDigit(r):
+		t.kind = dotID
+		t.text = l.numeral()
+		if t.text == "-" || t.text == "." || t.text == "-." {
+			return t, l.errorf(t.line, t.column, "invalid numeral %q", t.text)
+		}
+	case r == '_' || unicode.IsLetter(r) || r >= 0x80:
+		t.kind = dotID
+		t.text = l.identifier()
+	default:
+		return t, l.errorf(t.line, t.column, "unexpected character %q", r)
+	}
+
+	return t, nil
+}
+
+// quoted reads a double quoted string and return its content.
+func (l *dotLexer) quoted() (string, error) {
+	line, column := l.line, l.column
+	l.advance()
+
+	var b strings.Builder
+	for {
+		if l.pos >= len(l.src) {
+			return "", l.errorf(line, column, "unterminated string")
+		}
+
+		r := l.advance()
+		switch {
+		case r == '"':
+			return b.String(), nil
+		case r == '\\' && l.peek(0) == '"':
+			b.WriteRune(l.advance())
+		case r == '\\' && l.peek(0) == '\n':
+			// A line continuation.
+			l.advance()
+		default:
+			b.WriteRune(r)
+		}
+	}
+}
+
+// html reads an HTML string and return its content without the outer angle
+// brackets.
+func (l *dotLexer) html() (string, error) {
+	line, column := l.line, l.column
+	l.advance()
+
+	var b strings.Builder
+	for depth := 1; ; {
+		if l.pos >= len(l.src) {
+			return "", l.errorf(line, column, "unterminated HTML string")
+		}
+
+		r := l.advance()
+		switch r {
+		case '<':
+			depth++
+		case '>':
+			depth--
+			if depth == 0 {
+				return b.String(), nil
+			}
+		}
+		b.WriteRune(r)
+	}
+}
+
+// numeral reads a DOT numeral.
+func (l *dotLexer) numeral() string {
+	var b strings.Builder
+	if l.peek(0) == '-' {
+		b.WriteRune(l.advance())
+	}
+
+	dot := false
+	for l.pos < len(l.src) {
+		r := l.peek(0)
+		if r == '.' && !dot {
+			dot = true
+		} else if !unicode.IsDigit(r) {
+			break
+		}
+		b.WriteRune(l.advance())
+	}
+
+	return b.String()
+}
+
+// identifier reads an alphanumeric DOT identifier.
+func (l *dotLexer) identifier() string {
+	var b strings.Builder
+	for l.pos < len(l.src) {
+		r := l.peek(0)
+		if r != '_' && r < 0x80 && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			break
+		}
+		b.WriteRune(l.advance())
+	}
+
+	return b.String()
+}
+
+// dotScope holds the state of the graph or subgraph being parsed.
+type dotScope struct {
+	// nodeDefaults are the attributes given to nodes declared in the scope.
+	nodeDefaults map[string]string
+
+	// subgraph is the ID of the innermost named subgraph.
+	subgraph string
+}
+
+// dotParser builds a graph from DOT tokens.
+type dotParser struct {
+	lexer *dotLexer
+	tok   dotToken
+	dag   *DAG
+}
+
+// next moves to the next token.
+func (p *dotParser) next() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+
+	return nil
+}
+
+// errorf return a ParseError at the current token.
+func (p *dotParser) errorf(format string, args ...interface{}) error {
+	return p.lexer.errorf(p.tok.line, p.tok.column, format, args...)
+}
+
+// expect moves past the given punctuation character, or fails.
+func (p *dotParser) expect(punct string) error {
+	if !p.tok.is(punct) {
+		return p.errorf("expected %q but got %s", punct, p.tok)
+	}
+
+	return p.next()
+}
+
+// parseGraph parses: [strict] digraph [ID] '{' stmt_list '}'
+func (p *dotParser) parseGraph() error {
+	if p.tok.isKeyword("strict") {
+		if err := p.next(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case p.tok.isKeyword("digraph"):
+	case p.tok.isKeyword("graph"):
+		return p.errorf("undirected graphs are not supported")
+	default:
+		return p.errorf("expected \"digraph\" but got %s", p.tok)
+	}
+	if err := p.next(); err != nil {
+		return err
+	}
+
+	if p.tok.kind == dotID {
+		if err := p.next(); err != nil {
+			return err
+		}
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+
+	scope := &dotScope{nodeDefaults: map[string]string{}}
+	if _, err := p.parseStmtList(scope); err != nil {
+		return err
+	}
+
+	if err := p.expect("}"); err != nil {
+		return err
+	}
+	if p.tok.kind != dotEOF {
+		return p.errorf("expected end of input but got %s", p.tok)
+	}
+
+	return nil
+}
+
+// parseStmtList parses statements up to a closing brace, and return the
+// vertices they mention.
+func (p *dotParser) parseStmtList(scope *dotScope) ([]*Vertex, error) {
+	var mentioned []*Vertex
+
+	for !p.tok.is("}") {
+		if p.tok.kind == dotEOF {
+			return nil, p.errorf("expected \"}\" but got %s", p.tok)
+		}
+
+		vertices, err := p.parseStmt(scope)
+		if err != nil {
+			return nil, err
+		}
+		mentioned = append(mentioned, vertices...)
+
+		if p.tok.is(";") {
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return mentioned, nil
+}
+
+// parseStmt parses a single statement, and return the vertices it mentions.
+func (p *dotParser) parseStmt(scope *dotScope) ([]*Vertex, error) {
+	switch {
+	case p.tok.isKeyword("graph"), p.tok.isKeyword("node"), p.tok.isKeyword("edge"):
+		isNode := p.tok.isKeyword("node")
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		attrs, err := p.parseAttrLists()
+		if err != nil {
+			return nil, err
+		}
+		if isNode {
+			for key, value := range attrs {
+				scope.nodeDefaults[key] = value
+			}
+		}
+		return nil, nil
+	case p.tok.isKeyword("subgraph"), p.tok.is("{"):
+		vertices, err := p.parseSubgraph(scope)
+		if err != nil {
+			return nil, err
+		}
+		return p.parseEdgeStmt(scope, vertices)
+	case p.tok.kind == dotID:
+		id := p.tok
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		if p.tok.is("=") {
+			// A graph attribute.
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			if p.tok.kind != dotID {
+				return nil, p.errorf("expected attribute value but got %s", p.tok)
+			}
+			return nil, p.next()
+		}
+
+		if err := p.skipPort(); err != nil {
+			return nil, err
+		}
+		vertex, err := p.vertex(scope, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if p.tok.kind == dotEdgeOp {
+			return p.parseEdgeStmt(scope, []*Vertex{vertex})
+		}
+
+		attrs, err := p.parseAttrLists()
+		if err != nil {
+			return nil, err
+		}
+		if len(attrs) > 0 {
+			vertexAttrs := vertexAttributes(vertex)
+			for key, value := range attrs {
+				vertexAttrs[key] = value
+			}
+		}
+		return []*Vertex{vertex}, nil
+	}
+
+	return nil, p.errorf("unexpected %s", p.tok)
+}
+
+// parseSubgraph parses: [subgraph [ID]] '{' stmt_list '}'
+func (p *dotParser) parseSubgraph(parent *dotScope) ([]*Vertex, error) {
+	scope := &dotScope{
+		nodeDefaults: make(map[string]string, len(parent.nodeDefaults)),
+		subgraph:     parent.subgraph,
+	}
+	for key, value := range parent.nodeDefaults {
+		scope.nodeDefaults[key] = value
+	}
+
+	if p.tok.isKeyword("subgraph") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind == dotID {
+			scope.subgraph = p.tok.text
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	vertices, err := p.parseStmtList(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return vertices, p.expect("}")
+}
+
+// parseEdgeStmt parses the rest of an edge statement after its first end,
+// if any, and adds its edges to the graph. It return the vertices it
+// mentions.
+func (p *dotParser) parseEdgeStmt(scope *dotScope, tails []*Vertex) ([]*Vertex, error) {
+	mentioned := tails
+	var edges []*Edge
+
+	for p.tok.kind == dotEdgeOp {
+		op := p.tok
+		if op.text != "->" {
+			return nil, p.errorf("undirected edges are not supported")
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		var heads []*Vertex
+		switch {
+		case p.tok.isKeyword("subgraph"), p.tok.is("{"):
+			vertices, err := p.parseSubgraph(scope)
+			if err != nil {
+				return nil, err
+			}
+			heads = vertices
+		case p.tok.kind == dotID:
+			id := p.tok
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			if err := p.skipPort(); err != nil {
+				return nil, err
+			}
+			head, err := p.vertex(scope, id)
+			if err != nil {
+				return nil, err
+			}
+			heads = []*Vertex{head}
+		default:
+			return nil, p.errorf("expected node or subgraph but got %s", p.tok)
+		}
+
+		for _, tail := range tails {
+			for _, head := range heads {
+				if tail.Children.Contains(head) {
+					continue
+				}
+				if err := p.dag.AddEdge(tail, head); err != nil {
+					return nil, p.lexer.errorf(op.line, op.column, "%w", err)
+				}
+				edge, _ := p.dag.GetEdge(tail, head)
+				edges = append(edges, edge)
+			}
+		}
+
+		mentioned = append(mentioned, heads...)
+		tails = heads
+	}
+
+	attrsTok := p.tok
+	attrs, err := p.parseAttrLists()
+	if err != nil {
+		return nil, err
+	}
+	if len(attrs) == 0 {
+		return mentioned, nil
+	}
+
+	weight := 1.0
+	if value, found := attrs["weight"]; found {
+		weight, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, p.lexer.errorf(attrsTok.line, attrsTok.column, "invalid edge weight %q", value)
+		}
+	}
+	for _, edge := range edges {
+		edge.Label = attrs["label"]
+		edge.Weight = weight
+		for key, value := range attrs {
+			if key != "label" && key != "weight" {
+				edge.Attrs[key] = value
+			}
+		}
+	}
+
+	return mentioned, nil
+}
+
+// parseAttrLists parses zero or more: '[' [ID '=' ID [(';'|',')]]... ']'
+func (p *dotParser) parseAttrLists() (map[string]string, error) {
+	attrs := make(map[string]string)
+
+	for p.tok.is("[") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		for !p.tok.is("]") {
+			if p.tok.kind != dotID {
+				return nil, p.errorf("expected attribute name but got %s", p.tok)
+			}
+			key := p.tok.text
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			if p.tok.kind != dotID {
+				return nil, p.errorf("expected attribute value but got %s", p.tok)
+			}
+			attrs[key] = p.tok.text
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+
+			if p.tok.is(";") || p.tok.is(",") {
+				if err := p.next(); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	return attrs, nil
+}
+
+// skipPort skips the optional port of a node ID: [':' ID [':' ID]]
+func (p *dotParser) skipPort() error {
+	for i := 0; i < 2 && p.tok.is(":"); i++ {
+		if err := p.next(); err != nil {
+			return err
+		}
+		if p.tok.kind != dotID {
+			return p.errorf("expected port but got %s", p.tok)
+		}
+		if err := p.next(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// vertex return the vertex with the ID of the given token, adding it to the
+// graph with the default attributes of the scope if it doesn't exist yet.
+func (p *dotParser) vertex(scope *dotScope, id dotToken) (*Vertex, error) {
+	vertex, err := p.dag.GetVertex(id.text)
+	if err != nil {
+		vertex = NewVertex(id.text, nil)
+		if len(scope.nodeDefaults) > 0 {
+			attrs := vertexAttributes(vertex)
+			for key, value := range scope.nodeDefaults {
+				attrs[key] = value
+			}
+		}
+		if scope.subgraph != "" {
+			vertex.Metadata[DOTSubgraphKey] = scope.subgraph
+		}
+		if err := p.dag.AddVertex(vertex); err != nil {
+			return nil, p.lexer.errorf(id.line, id.column, "%w", err)
+		}
+	}
+
+	return vertex, nil
+}
+
+// vertexAttributes return the DOT attributes of a vertex, creating them if
+// needed.
+func vertexAttributes(vertex *Vertex) map[string]string {
+	attrs, ok := vertex.Metadata[DOTAttributesKey].(map[string]string)
+	if !ok {
+		attrs = make(map[string]string)
+		vertex.Metadata[DOTAttributesKey] = attrs
+	}
+
+	return attrs
+}
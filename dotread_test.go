@@ -0,0 +1,352 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+// edgeIDs return the edges of the graph as "tail->head" strings.
+func edgeIDs(dag1 *dag.DAG) []string {
+	var edges []string
+	for _, vertex := range dag1.Vertices() {
+		for _, child := range vertex.Children.Values() {
+			edges = append(edges, vertex.ID+"->"+child.(*dag.Vertex).ID)
+		}
+	}
+
+	return edges
+}
+
+func TestReadDOT(t *testing.T) {
+	src := `// A legacy pipeline.
+/* Block
+   comment */
+strict digraph "pipeline" {
+	rankdir=LR
+	node [shape=box];
+	extract [label="Extract \"raw\" data"];
+	extract -> transform -> load [color=red];
+	extract -> { report; audit } -> archive;
+	"multi
+word" -> archive:n:s;
+	subgraph cluster_checks {
+		node [color=blue]
+		check -> archive
+	}
+	lone [label=<<b>bold</b>>, weight=-1.5]
+}
+`
+
+	dag1, err := dag.ReadDOT(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Can't read DOT: %s", err)
+	}
+
+	expectedVertices := []string{"extract", "transform", "load", "report", "audit", "archive", "multi\nword", "check", "lone"}
+	if !reflect.DeepEqual(vertexIDs(dag1.Vertices()), expectedVertices) {
+		t.Fatalf("Expected vertices to be %q but got %q", expectedVertices, vertexIDs(dag1.Vertices()))
+	}
+
+	expectedEdges := []string{
+		"extract->transform", "extract->report", "extract->audit",
+		"transform->load", "report->archive", "audit->archive",
+		"multi\nword->archive", "check->archive",
+	}
+	if !reflect.DeepEqual(edgeIDs(dag1), expectedEdges) {
+		t.Fatalf("Expected edges to be %q but got %q", expectedEdges, edgeIDs(dag1))
+	}
+
+	extract, _ := dag1.GetVertex("extract")
+	expectedAttrs := map[string]string{"shape": "box", "label": `Extract "raw" data`}
+	if !reflect.DeepEqual(extract.Metadata[dag.DOTAttributesKey], expectedAttrs) {
+		t.Fatalf("Expected extract attributes to be %v but got %v", expectedAttrs, extract.Metadata[dag.DOTAttributesKey])
+	}
+
+	check, _ := dag1.GetVertex("check")
+	expectedAttrs = map[string]string{"shape": "box", "color": "blue"}
+	if !reflect.DeepEqual(check.Metadata[dag.DOTAttributesKey], expectedAttrs) {
+		t.Fatalf("Expected check attributes to be %v but got %v", expectedAttrs, check.Metadata[dag.DOTAttributesKey])
+	}
+	if check.Metadata[dag.DOTSubgraphKey] != "cluster_checks" {
+		t.Fatalf("Expected check subgraph to be %q but got %v", "cluster_checks", check.Metadata[dag.DOTSubgraphKey])
+	}
+
+	archive, _ := dag1.GetVertex("archive")
+	if _, found := archive.Metadata[dag.DOTSubgraphKey]; found {
+		t.Fatalf("Expected archive to have no subgraph, as it was declared outside")
+	}
+
+	lone, _ := dag1.GetVertex("lone")
+	expectedAttrs = map[string]string{"shape": "box", "label": "<b>bold</b>", "weight": "-1.5"}
+	if !reflect.DeepEqual(lone.Metadata[dag.DOTAttributesKey], expectedAttrs) {
+		t.Fatalf("Expected lone attributes to be %v but got %v", expectedAttrs, lone.Metadata[dag.DOTAttributesKey])
+	}
+}
+
+func TestReadDOT_RoundTrip(t *testing.T) {
+	dag1, _ := newTestDAG(t,
+		[]string{"1", "2", "3", "4"},
+		[][2]string{{"1", "2"}, {"1", "3"}, {"2", "4"}, {"3", "4"}},
+	)
+
+	var buf bytes.Buffer
+	err := dag1.WriteDOT(&buf, &dag.DOTOptions{RankDir: "LR", Label: dag.ValueLabel})
+	if err != nil {
+		t.Fatalf("Can't write DOT: %s", err)
+	}
+
+	dag2, err := dag.ReadDOT(&buf)
+	if err != nil {
+		t.Fatalf("Can't read DOT: %s", err)
+	}
+
+	if !reflect.DeepEqual(vertexIDs(dag2.Vertices()), vertexIDs(dag1.Vertices())) {
+		t.Fatalf("Expected vertices to be %v but got %v", vertexIDs(dag1.Vertices()), vertexIDs(dag2.Vertices()))
+	}
+	if !reflect.DeepEqual(edgeIDs(dag2), edgeIDs(dag1)) {
+		t.Fatalf("Expected edges to be %v but got %v", edgeIDs(dag1), edgeIDs(dag2))
+	}
+}
+
+func TestReadDOT_RoundTripQuoting(t *testing.T) {
+	ids := []string{`a\`, `b\\`, `say "hi"`, `"quoted\"`, "two\nlines", "back\\\nslash"}
+
+	dag1 := dag.NewDAG()
+	var vertices []*dag.Vertex
+	for i, id := range ids {
+		vertex := dag.NewVertex(id, ids[len(ids)-1-i])
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+		vertices = append(vertices, vertex)
+	}
+	for i := 1; i < len(vertices); i++ {
+		err := dag1.AddEdgeWithAttrs(vertices[i-1], vertices[i], vertices[i].ID, 1, nil)
+		if err != nil {
+			t.Fatalf("Can't add edge to DAG: %s", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	err := dag1.WriteDOT(&buf, &dag.DOTOptions{Label: dag.ValueLabel})
+	if err != nil {
+		t.Fatalf("Can't write DOT: %s", err)
+	}
+
+	dag2, err := dag.ReadDOT(&buf)
+	if err != nil {
+		t.Fatalf("Can't read DOT: %s", err)
+	}
+
+	if !reflect.DeepEqual(vertexIDs(dag2.Vertices()), ids) {
+		t.Fatalf("Expected vertices to be %q but got %q", ids, vertexIDs(dag2.Vertices()))
+	}
+	for i, vertex := range dag2.Vertices() {
+		attrs, _ := vertex.Metadata[dag.DOTAttributesKey].(map[string]string)
+		if attrs["label"] != vertices[i].Value {
+			t.Fatalf("Expected vertex %q label to be %q but got %q", vertex.ID, vertices[i].Value, attrs["label"])
+		}
+	}
+	for _, edge := range dag2.Edges() {
+		if edge.Label != edge.Head.ID {
+			t.Fatalf("Expected edge %s label to be %q but got %q", edge, edge.Head.ID, edge.Label)
+		}
+	}
+}
+
+func TestReadDOT_RoundTripEdges(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"a", "b", "c"},
+		nil,
+	)
+	err := dag1.AddEdgeWithAttrs(vertices["b"], vertices["c"], "", math.Inf(-1), nil)
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+	err = dag1.AddEdgeWithAttrs(vertices["a"], vertices["b"], "rows", 3, map[string]interface{}{
+		"k":         "v",
+		"color":     "blue",
+		"two words": "x\"y",
+		"node":      "n",
+		"retries":   2,
+	})
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+
+	var buf bytes.Buffer
+	err = dag1.WriteDOT(&buf, nil)
+	if err != nil {
+		t.Fatalf("Can't write DOT: %s", err)
+	}
+
+	expected := `digraph {
+	"a";
+	"b";
+	"c";
+	"a" -> "b" [label="rows", weight=3, color="blue", k="v", "node"="n", retries="2", "two words"="x\"y"];
+	"b" -> "c" [weight="-Inf"];
+}
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected DOT output to be %q but got %q", expected, buf.String())
+	}
+
+	dag2, err := dag.ReadDOT(&buf)
+	if err != nil {
+		t.Fatalf("Can't read DOT: %s", err)
+	}
+
+	tests := []struct {
+		tail   string
+		head   string
+		label  string
+		weight float64
+		attrs  map[string]interface{}
+	}{
+		{"a", "b", "rows", 3, map[string]interface{}{
+			"k": "v", "color": "blue", "two words": "x\"y", "node": "n", "retries": "2",
+		}},
+		{"b", "c", "", math.Inf(-1), map[string]interface{}{}},
+	}
+
+	for _, test := range tests {
+		tail, _ := dag2.GetVertex(test.tail)
+		head, _ := dag2.GetVertex(test.head)
+		edge, err := dag2.GetEdge(tail, head)
+		if err != nil {
+			t.Fatalf("Can't get edge: %s", err)
+		}
+		if edge.Label != test.label || edge.Weight != test.weight || !reflect.DeepEqual(edge.Attrs, test.attrs) {
+			t.Fatalf("Expected edge (%s,%s) to be %q weighing %v with %v but got %s with %v",
+				test.tail, test.head, test.label, test.weight, test.attrs, edge, edge.Attrs)
+		}
+	}
+
+	// Attrs can't hold the attributes written from the edge fields.
+	b, _ := dag2.GetVertex("b")
+	c, _ := dag2.GetVertex("c")
+	edge, _ := dag2.GetEdge(b, c)
+	edge.Attrs["weight"] = "2"
+	err = dag2.WriteDOT(&buf, nil)
+	if err == nil {
+		t.Fatalf("Edge has a weight attribute, WriteDOT should fail but it doesn't")
+	}
+}
+
+func TestReadDOT_Cycle(t *testing.T) {
+	src := `digraph {
+	a -> b -> c
+	c -> a
+}`
+
+	_, err := dag.ReadDOT(strings.NewReader(src))
+
+	var parseErr *dag.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Expected error to be a *dag.ParseError but got %v", err)
+	}
+	if parseErr.Line != 3 || parseErr.Column != 4 {
+		t.Fatalf("Expected error at 3:4 but got %d:%d", parseErr.Line, parseErr.Column)
+	}
+
+	var cycleErr *dag.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected error to wrap a *dag.CycleError but got %v", err)
+	}
+
+	expectedMsg := "3:4: cycle detected: c -> a -> b -> c"
+	if err.Error() != expectedMsg {
+		t.Fatalf("Expected error message to be %q but got %q", expectedMsg, err.Error())
+	}
+}
+
+func TestReadDOT_EdgeAttributes(t *testing.T) {
+	src := `digraph {
+	a -> b [label="joins on id", weight=2.5, color=blue];
+	a -> c;
+	b -> c [style=dashed];
+}`
+
+	dag1, err := dag.ReadDOT(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Can't read DOT: %s", err)
+	}
+
+	tests := []struct {
+		tail   string
+		head   string
+		label  string
+		weight float64
+		attrs  map[string]interface{}
+	}{
+		{"a", "b", "joins on id", 2.5, map[string]interface{}{"color": "blue"}},
+		{"a", "c", "", 1, map[string]interface{}{}},
+		{"b", "c", "", 1, map[string]interface{}{"style": "dashed"}},
+	}
+
+	for _, test := range tests {
+		tail, _ := dag1.GetVertex(test.tail)
+		head, _ := dag1.GetVertex(test.head)
+		edge, err := dag1.GetEdge(tail, head)
+		if err != nil {
+			t.Fatalf("Can't get edge: %s", err)
+		}
+		if edge.Label != test.label || edge.Weight != test.weight || !reflect.DeepEqual(edge.Attrs, test.attrs) {
+			t.Fatalf("Expected edge (%s,%s) to be %q weighing %v with %v but got %s with %v",
+				test.tail, test.head, test.label, test.weight, test.attrs, edge, edge.Attrs)
+		}
+	}
+}
+
+func TestReadDOT_Errors(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{"graph { a -- b }", "1:1: undirected graphs are not supported"},
+		{"digraph { a -- b }", "1:13: undirected edges are not supported"},
+		{"digraph {\n  a -> }", "2:8: expected node or subgraph but got }"},
+		{"digraph { a [label] }", "1:19: expected \"=\" but got ]"},
+		{"digraph { a -> b", "1:17: expected \"}\" but got end of input"},
+		{"digraph { \"a }", "1:11: unterminated string"},
+		{"digraph { /* a }", "1:11: unterminated comment"},
+		{"digraph { a } b", "1:15: expected end of input but got \"b\""},
+		{"digraph { a @ b }", "1:13: unexpected character '@'"},
+		{"subgraph { a }", "1:1: expected \"digraph\" but got \"subgraph\""},
+		{"digraph { a -> b [weight=x] }", "1:18: invalid edge weight \"x\""},
+	}
+
+	for _, test := range tests {
+		_, err := dag.ReadDOT(strings.NewReader(test.src))
+		if err == nil {
+			t.Fatalf("DOT %q is invalid, ReadDOT should fail but it doesn't", test.src)
+		}
+		if err.Error() != test.expected {
+			t.Fatalf("Expected error message for %q to be %q but got %q", test.src, test.expected, err.Error())
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag
+
+import (
+	"fmt"
+)
+
+// Edge type implements a directed edge between two vertices of a graph,
+// with the data it carries.
+type Edge struct {
+	Tail *Vertex
+	Head *Vertex
+
+	// Label describes the edge, such as why the head depends on the tail.
+	Label string
+
+	// Weight is the weight of the edge, used by EdgeWeight. Edges added
+	// with AddEdge weigh 1.
+	Weight float64
+
+	// Attrs holds any other data about the edge. Keys should be namespaced
+	// by whoever sets them, like vertex metadata.
+	Attrs map[string]interface{}
+}
+
+// String implements stringer interface and prints an string representation
+// of this instance.
+func (e *Edge) String() string {
+	result := fmt.Sprintf("Edge: %s -> %s - Label: %q - Weight: %v\n", e.Tail.ID, e.Head.ID, e.Label, e.Weight)
+
+	return result
+}
+
+// edgeKey identifies an edge in the edges of a graph.
+type edgeKey struct {
+	tail *Vertex
+	head *Vertex
+}
+
+// AddEdgeWithAttrs adds a directed edge between two existing vertices to the
+// graph, with the given label, weight and attributes. The attributes are
+// kept as given, and replaced by an empty map if nil.
+//
+// It fails as AddEdge does.
+func (d *DAG) AddEdgeWithAttrs(tailVertex *Vertex, headVertex *Vertex, label string, weight float64, attrs map[string]interface{}) error {
+	if attrs == nil {
+		attrs = make(map[string]interface{})
+	}
+
+	return d.addEdge(&Edge{
+		Tail:   tailVertex,
+		Head:   headVertex,
+		Label:  label,
+		Weight: weight,
+		Attrs:  attrs,
+	})
+}
+
+// GetEdge return the edge from one vertex to another. Changes to the edge
+// returned are seen by the graph.
+func (d *DAG) GetEdge(tailVertex *Vertex, headVertex *Vertex) (*Edge, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.containsVertex(tailVertex) {
+		return nil, fmt.Errorf("vertex %s not found in the graph", tailVertex.ID)
+	}
+	if !d.containsVertex(headVertex) {
+		return nil, fmt.Errorf("vertex %s not found in the graph", headVertex.ID)
+	}
+	if !tailVertex.Children.Contains(headVertex) {
+		return nil, fmt.Errorf("edge (%s,%s) not found in the graph", tailVertex.ID, headVertex.ID)
+	}
+
+	return d.edge(tailVertex, headVertex), nil
+}
+
+// Edges return the edges of the graph, by tail vertex insertion order and
+// then by child order.
+func (d *DAG) Edges() []*Edge {
+	var edges []*Edge
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, vertex := range d.Vertices() {
+		for _, child := range vertex.Children.Values() {
+			edges = append(edges, d.edge(vertex, child.(*Vertex)))
+		}
+	}
+
+	return edges
+}
+
+// InEdges return the edges entering a vertex, in the order of its parents.
+func (d *DAG) InEdges(vertex *Vertex) ([]*Edge, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var edges []*Edge
+
+	if !d.containsVertex(vertex) {
+		return edges, fmt.Errorf("vertex %s not found in the graph", vertex.ID)
+	}
+
+	for _, parent := range vertex.Parents.Values() {
+		edges = append(edges, d.edge(parent.(*Vertex), vertex))
+	}
+
+	return edges, nil
+}
+
+// OutEdges return the edges leaving a vertex, in the order of its children.
+func (d *DAG) OutEdges(vertex *Vertex) ([]*Edge, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var edges []*Edge
+
+	if !d.containsVertex(vertex) {
+		return edges, fmt.Errorf("vertex %s not found in the graph", vertex.ID)
+	}
+
+	for _, child := range vertex.Children.Values() {
+		edges = append(edges, d.edge(vertex, child.(*Vertex)))
+	}
+
+	return edges, nil
+}
+
+// EdgeWeight return the Weight of the edge from one vertex to another, or 1
+// if there is no such edge. It can be given as the weight of ShortestPath,
+// KShortestPaths or CriticalPathOptions.EdgeWeight.
+func (d *DAG) EdgeWeight(tailVertex *Vertex, headVertex *Vertex) float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if edge, found := d.edges[edgeKey{tailVertex, headVertex}]; found {
+		return edge.Weight
+	}
+
+	return 1
+}
+
+// edge return the edge from one vertex to another, which must be a child of
+// the first one. Edges not added through the graph, by changing Children
+// and Parents directly, are stored with the defaults on first use, so
+// changes to them are seen by the graph too. The caller must hold d.mu, as
+// edges is changed with it held.
+func (d *DAG) edge(tailVertex *Vertex, headVertex *Vertex) *Edge {
+	key := edgeKey{tailVertex, headVertex}
+	if edge, found := d.edges[key]; found {
+		return edge
+	}
+
+	edge := newEdge(tailVertex, headVertex)
+	if d.edges == nil {
+		d.edges = make(map[edgeKey]*Edge)
+	}
+	d.edges[key] = edge
+
+	return edge
+}
+
+// newEdge creates an edge with the defaults of AddEdge.
+func newEdge(tailVertex *Vertex, headVertex *Vertex) *Edge {
+	e := &Edge{
+		Tail:   tailVertex,
+		Head:   headVertex,
+		Weight: 1,
+		Attrs:  make(map[string]interface{}),
+	}
+
+	return e
+}
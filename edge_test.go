@@ -0,0 +1,273 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+// edgeLabels return the edges as "tail->head:label" strings.
+func edgeLabels(edges []*dag.Edge) []string {
+	labels := []string{}
+	for _, edge := range edges {
+		labels = append(labels, edge.Tail.ID+"->"+edge.Head.ID+":"+edge.Label)
+	}
+
+	return labels
+}
+
+func TestDAG_AddEdgeWithAttrs(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"orders", "customers", "report"},
+		[][2]string{{"orders", "report"}},
+	)
+
+	attrs := map[string]interface{}{"lineage.columns": []string{"customer_id"}}
+	err := dag1.AddEdgeWithAttrs(vertices["customers"], vertices["report"], "joined on customer_id", 3, attrs)
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+
+	edge, err := dag1.GetEdge(vertices["customers"], vertices["report"])
+	if err != nil {
+		t.Fatalf("Can't get edge: %s", err)
+	}
+	if edge.Tail != vertices["customers"] || edge.Head != vertices["report"] {
+		t.Fatalf("Expected edge to be (customers,report) but got (%s,%s)", edge.Tail.ID, edge.Head.ID)
+	}
+	if edge.Label != "joined on customer_id" || edge.Weight != 3 || !reflect.DeepEqual(edge.Attrs, attrs) {
+		t.Fatalf("Expected edge data to be kept but got %s with %v", edge, edge.Attrs)
+	}
+	if dag1.EdgeWeight(vertices["customers"], vertices["report"]) != 3 {
+		t.Fatalf("Expected edge weight to be 3 but got %v", dag1.EdgeWeight(vertices["customers"], vertices["report"]))
+	}
+
+	// Edges added with AddEdge get the defaults.
+	edge, err = dag1.GetEdge(vertices["orders"], vertices["report"])
+	if err != nil {
+		t.Fatalf("Can't get edge: %s", err)
+	}
+	if edge.Label != "" || edge.Weight != 1 || edge.Attrs == nil || len(edge.Attrs) != 0 {
+		t.Fatalf("Expected edge to have the defaults but got %s with %v", edge, edge.Attrs)
+	}
+
+	// Changes to an edge are seen by the graph.
+	edge.Label = "aggregated"
+	edge.Weight = 2
+	if dag1.EdgeWeight(vertices["orders"], vertices["report"]) != 2 {
+		t.Fatalf("Expected edge weight to be 2 but got %v", dag1.EdgeWeight(vertices["orders"], vertices["report"]))
+	}
+
+	expected := []string{"orders->report:aggregated", "customers->report:joined on customer_id"}
+	if !reflect.DeepEqual(edgeLabels(dag1.Edges()), expected) {
+		t.Fatalf("Expected edges to be %v but got %v", expected, edgeLabels(dag1.Edges()))
+	}
+
+	inEdges, err := dag1.InEdges(vertices["report"])
+	if err != nil {
+		t.Fatalf("Can't get edges: %s", err)
+	}
+	if !reflect.DeepEqual(edgeLabels(inEdges), expected) {
+		t.Fatalf("Expected edges entering report to be %v but got %v", expected, edgeLabels(inEdges))
+	}
+	outEdges, err := dag1.OutEdges(vertices["customers"])
+	if err != nil {
+		t.Fatalf("Can't get edges: %s", err)
+	}
+	if !reflect.DeepEqual(edgeLabels(outEdges), expected[1:]) {
+		t.Fatalf("Expected edges leaving customers to be %v but got %v", expected[1:], edgeLabels(outEdges))
+	}
+
+	// Adding an edge again fails and keeps its data.
+	err = dag1.AddEdgeWithAttrs(vertices["customers"], vertices["report"], "other", 1, nil)
+	if err == nil {
+		t.Fatalf("Edge already exists, AddEdgeWithAttrs should fail but it doesn't")
+	}
+	edge, _ = dag1.GetEdge(vertices["customers"], vertices["report"])
+	if edge.Label != "joined on customer_id" {
+		t.Fatalf("Expected edge label to be kept but got %q", edge.Label)
+	}
+}
+
+func TestDAG_GetEdge_Fails(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"1", "2"},
+		[][2]string{{"1", "2"}},
+	)
+
+	tests := []struct {
+		tail     *dag.Vertex
+		head     *dag.Vertex
+		expected string
+	}{
+		{dag.NewVertex("1", nil), vertices["2"], "vertex 1 not found in the graph"},
+		{vertices["1"], dag.NewVertex("3", nil), "vertex 3 not found in the graph"},
+		{vertices["2"], vertices["1"], "edge (2,1) not found in the graph"},
+	}
+
+	for _, test := range tests {
+		_, err := dag1.GetEdge(test.tail, test.head)
+		if err == nil {
+			t.Fatalf("Edge doesn't belong to the graph, GetEdge should fail but it doesn't")
+		}
+		if err.Error() != test.expected {
+			t.Fatalf("Expected error message to be %q but got %q", test.expected, err.Error())
+		}
+	}
+
+	_, err := dag1.InEdges(dag.NewVertex("3", nil))
+	if err == nil {
+		t.Fatalf("Vertex doesn't belong to the graph, InEdges should fail but it doesn't")
+	}
+	_, err = dag1.OutEdges(dag.NewVertex("3", nil))
+	if err == nil {
+		t.Fatalf("Vertex doesn't belong to the graph, OutEdges should fail but it doesn't")
+	}
+}
+
+func TestDAG_DeleteEdge_DeletesAttrs(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"1", "2", "3"},
+		nil,
+	)
+
+	for _, edge := range [][2]string{{"1", "2"}, {"2", "3"}} {
+		err := dag1.AddEdgeWithAttrs(vertices[edge[0]], vertices[edge[1]], "x", 5, nil)
+		if err != nil {
+			t.Fatalf("Can't add edge to DAG: %s", err)
+		}
+	}
+
+	err := dag1.DeleteEdge(vertices["1"], vertices["2"])
+	if err != nil {
+		t.Fatalf("Can't delete edge from DAG: %s", err)
+	}
+	err = dag1.DeleteVertex(vertices["3"])
+	if err != nil {
+		t.Fatalf("Can't delete vertex from DAG: %s", err)
+	}
+	err = dag1.AddVertex(vertices["3"])
+	if err != nil {
+		t.Fatalf("Can't add vertex to DAG: %s", err)
+	}
+
+	for _, edge := range [][2]string{{"1", "2"}, {"2", "3"}} {
+		err := dag1.AddEdge(vertices[edge[0]], vertices[edge[1]])
+		if err != nil {
+			t.Fatalf("Can't add edge to DAG: %s", err)
+		}
+	}
+
+	for _, edge := range dag1.Edges() {
+		if edge.Label != "" || edge.Weight != 1 {
+			t.Fatalf("Expected edge added again to have the defaults but got %s", edge)
+		}
+	}
+}
+
+func TestDAG_GetEdge_ChildrenChanged(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"1", "2"},
+		nil,
+	)
+
+	// An edge added by hand has the defaults, and is kept once changed.
+	vertices["1"].Children.Add(vertices["2"])
+	vertices["2"].Parents.Add(vertices["1"])
+
+	edge, err := dag1.GetEdge(vertices["1"], vertices["2"])
+	if err != nil {
+		t.Fatalf("Can't get edge: %s", err)
+	}
+	if edge.Label != "" || edge.Weight != 1 {
+		t.Fatalf("Expected edge added by hand to have the defaults but got %s", edge)
+	}
+	edge.Label = "x"
+	edge.Weight = 5
+
+	again, err := dag1.GetEdge(vertices["1"], vertices["2"])
+	if err != nil {
+		t.Fatalf("Can't get edge: %s", err)
+	}
+	if again != edge {
+		t.Fatalf("Expected GetEdge to return the same edge but got %s", again)
+	}
+	if edges := dag1.Edges(); len(edges) != 1 || edges[0] != edge {
+		t.Fatalf("Expected Edges to return the changed edge but got %v", edges)
+	}
+	if weight := dag1.EdgeWeight(vertices["1"], vertices["2"]); weight != 5 {
+		t.Fatalf("Expected edge weight to be 5 but got %v", weight)
+	}
+}
+
+func TestDAG_ShortestPath_EdgeWeight(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"a", "b", "c"},
+		[][2]string{{"a", "c"}, {"b", "c"}},
+	)
+	err := dag1.AddEdgeWithAttrs(vertices["a"], vertices["b"], "", 0.5, nil)
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+	edge, _ := dag1.GetEdge(vertices["a"], vertices["c"])
+	edge.Weight = 2
+
+	path, weight, err := dag1.ShortestPath(vertices["a"], vertices["c"], nil)
+	if err != nil {
+		t.Fatalf("Can't get shortest path: %s", err)
+	}
+	if !reflect.DeepEqual(vertexIDs(path), []string{"a", "b", "c"}) || weight != 1.5 {
+		t.Fatalf("Expected shortest path to be [a b c] weighing 1.5 but got %v weighing %v", vertexIDs(path), weight)
+	}
+}
+
+func TestDAG_Edges_Concurrent(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"0", "1", "2", "3", "4", "5", "6", "7"},
+		nil,
+	)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			for j := 1; j < 8; j++ {
+				tail, head := vertices["0"], vertices[fmt.Sprint(j)]
+				_ = dag1.AddEdgeWithAttrs(tail, head, "x", 2, nil)
+				_ = dag1.DeleteEdge(tail, head)
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		for _, edge := range dag1.Edges() {
+			_ = dag1.EdgeWeight(edge.Tail, edge.Head)
+		}
+		_, _ = dag1.OutEdges(vertices["0"])
+		_, _ = dag1.InEdges(vertices["1"])
+		_, _ = dag1.GetEdge(vertices["0"], vertices["1"])
+	}
+}
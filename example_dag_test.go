@@ -19,6 +19,7 @@ package dag_test
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/goombaio/dag"
 )
@@ -120,3 +121,89 @@ func ExampleDAG_edges() {
 	// ID: 3 - Parents: 1 - Children: 1 - Value: <nil>
 	// ID: 4 - Parents: 1 - Children: 0 - Value: <nil>
 }
+
+func ExampleDAG_TopologicalSortStable() {
+	dag1 := dag.NewDAG()
+
+	vertex1 := dag.NewVertex("1", nil)
+	vertex2 := dag.NewVertex("2", nil)
+	vertex3 := dag.NewVertex("3", nil)
+	vertex4 := dag.NewVertex("4", nil)
+
+	for _, vertex := range []*dag.Vertex{vertex4, vertex3, vertex2, vertex1} {
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			fmt.Printf("Can't add vertex to DAG: %s", err)
+			panic(err)
+		}
+	}
+
+	err := dag1.AddEdge(vertex1, vertex2)
+	if err != nil {
+		fmt.Printf("Can't add edge to DAG: %s", err)
+		panic(err)
+	}
+	err = dag1.AddEdge(vertex2, vertex3)
+	if err != nil {
+		fmt.Printf("Can't add edge to DAG: %s", err)
+		panic(err)
+	}
+	err = dag1.AddEdge(vertex1, vertex4)
+	if err != nil {
+		fmt.Printf("Can't add edge to DAG: %s", err)
+		panic(err)
+	}
+
+	sorted, err := dag1.TopologicalSortStable()
+	if err != nil {
+		fmt.Printf("Can't sort DAG: %s", err)
+		panic(err)
+	}
+
+	for _, vertex := range sorted {
+		fmt.Println(vertex.ID)
+	}
+	// Output:
+	// 1
+	// 4
+	// 2
+	// 3
+}
+
+func ExampleDAG_RenderText() {
+	dag1 := dag.NewDAG()
+
+	fetch := dag.NewVertex("fetch", nil)
+	build := dag.NewVertex("build", nil)
+	lint := dag.NewVertex("lint", nil)
+	release := dag.NewVertex("release", nil)
+
+	for _, vertex := range []*dag.Vertex{fetch, build, lint, release} {
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			fmt.Printf("Can't add vertex to DAG: %s", err)
+			panic(err)
+		}
+	}
+
+	for _, edge := range [][2]*dag.Vertex{{fetch, build}, {fetch, lint}, {build, release}, {lint, release}} {
+		err := dag1.AddEdge(edge[0], edge[1])
+		if err != nil {
+			fmt.Printf("Can't add edge to DAG: %s", err)
+			panic(err)
+		}
+	}
+
+	err := dag1.RenderText(os.Stdout, &dag.TextOptions{ASCII: true})
+	if err != nil {
+		fmt.Printf("Can't render DAG: %s", err)
+		panic(err)
+	}
+	// Output:
+	// *  fetch
+	// +-.
+	// * |  build
+	// | *  lint
+	// +-'
+	// *  release
+}
@@ -0,0 +1,136 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// CheckpointStore records the vertices completed by a run, so a later run of
+// the same graph can resume from there.
+type CheckpointStore interface {
+	// Load return the outputs of the completed vertices, by vertex ID.
+	Load() (map[string][]byte, error)
+
+	// Save records a vertex has completed with the given output.
+	Save(id string, output []byte) error
+}
+
+// FileCheckpointStore is a CheckpointStore backed by a local file.
+//
+// Completed vertices are appended to the file as JSON lines and synced to
+// disk, so a process dying halfway through a run loses at most the vertex
+// being saved.
+type FileCheckpointStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// checkpointEntry is a line of a checkpoint file.
+type checkpointEntry struct {
+	ID     string `json:"id"`
+	Output []byte `json:"output,omitempty"`
+}
+
+// NewFileCheckpointStore creates a new checkpoint store backed by the file at
+// the given path. The file is created on the first save.
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	s := &FileCheckpointStore{
+		path: path,
+	}
+
+	return s
+}
+
+// Load return the outputs of the completed vertices, by vertex ID. A missing
+// file means no vertex has completed yet.
+func (s *FileCheckpointStore) Load() (map[string][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	completed := make(map[string][]byte)
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return completed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry checkpointEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// A line cut short by a crash while saving; the vertex will
+			// just run again.
+			continue
+		}
+		completed[entry.ID] = entry.Output
+	}
+
+	return completed, scanner.Err()
+}
+
+// Save records a vertex has completed with the given output.
+func (s *FileCheckpointStore) Save(id string, output []byte) error {
+	line, err := json.Marshal(&checkpointEntry{ID: id, Output: output})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	// Start on a new line in case the previous save was cut short.
+	_, err = f.Write(append(append([]byte{'\n'}, line...), '\n'))
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// Clear deletes the checkpoint file, so the next run starts from scratch.
+func (s *FileCheckpointStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := os.Remove(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
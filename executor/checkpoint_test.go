@@ -0,0 +1,210 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/goombaio/dag"
+	"github.com/goombaio/dag/executor"
+)
+
+func TestFileCheckpointStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	store := executor.NewFileCheckpointStore(path)
+
+	completed, err := store.Load()
+	if err != nil {
+		t.Fatalf("Can't load checkpoint: %s", err)
+	}
+	if len(completed) != 0 {
+		t.Fatalf("Expected 0 completed vertices but got %d", len(completed))
+	}
+
+	err = store.Save("1", []byte("one"))
+	if err != nil {
+		t.Fatalf("Can't save checkpoint: %s", err)
+	}
+	err = store.Save("2", nil)
+	if err != nil {
+		t.Fatalf("Can't save checkpoint: %s", err)
+	}
+
+	completed, err = executor.NewFileCheckpointStore(path).Load()
+	if err != nil {
+		t.Fatalf("Can't load checkpoint: %s", err)
+	}
+	expected := map[string][]byte{"1": []byte("one"), "2": nil}
+	if !reflect.DeepEqual(completed, expected) {
+		t.Fatalf("Expected completed vertices to be %v but got %v", expected, completed)
+	}
+
+	err = store.Clear()
+	if err != nil {
+		t.Fatalf("Can't clear checkpoint: %s", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("Checkpoint file expected to be deleted")
+	}
+	err = store.Clear()
+	if err != nil {
+		t.Fatalf("Can't clear missing checkpoint: %s", err)
+	}
+}
+
+func TestFileCheckpointStore_TruncatedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	store := executor.NewFileCheckpointStore(path)
+
+	err := store.Save("1", nil)
+	if err != nil {
+		t.Fatalf("Can't save checkpoint: %s", err)
+	}
+
+	// Simulate a crash halfway through a save.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Can't open checkpoint: %s", err)
+	}
+	_, err = f.WriteString(`{"id":"2","outp`)
+	if err != nil {
+		t.Fatalf("Can't write checkpoint: %s", err)
+	}
+	f.Close()
+
+	err = store.Save("3", nil)
+	if err != nil {
+		t.Fatalf("Can't save checkpoint: %s", err)
+	}
+
+	completed, err := store.Load()
+	if err != nil {
+		t.Fatalf("Can't load checkpoint: %s", err)
+	}
+	expected := map[string][]byte{"1": nil, "3": nil}
+	if !reflect.DeepEqual(completed, expected) {
+		t.Fatalf("Expected completed vertices to be %v but got %v", expected, completed)
+	}
+}
+
+func TestExecutor_Run_Resume(t *testing.T) {
+	dag1 := newTestDAG(t,
+		[]string{"1", "2", "3", "4"},
+		[][2]string{{"1", "2"}, {"2", "3"}, {"1", "4"}},
+	)
+	store := executor.NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint"))
+
+	var mu sync.Mutex
+	var ran []string
+	failing := "3"
+
+	task := func(ctx context.Context, v *dag.Vertex) error {
+		mu.Lock()
+		ran = append(ran, v.ID)
+		mu.Unlock()
+
+		if v.ID == failing {
+			return errors.New("boom")
+		}
+
+		input := ""
+		for _, parent := range v.Parents.Values() {
+			output, _ := executor.Output(ctx, parent.(*dag.Vertex))
+			input += string(output)
+		}
+		executor.SetOutput(ctx, []byte(input+v.ID))
+
+		return nil
+	}
+
+	e := executor.NewExecutor(dag1, task)
+	e.Workers = 1
+	e.FailurePolicy = executor.SkipDownstream
+	e.Checkpoint = store
+
+	_, err := e.Run(context.Background())
+	if err == nil {
+		t.Fatalf("Vertex fails, Run should fail but it doesn't")
+	}
+	if !reflect.DeepEqual(ran, []string{"1", "2", "3", "4"}) {
+		t.Fatalf("Expected first run to run %v but got %v", []string{"1", "2", "3", "4"}, ran)
+	}
+
+	// Resume once the failure is fixed.
+	ran = nil
+	failing = ""
+
+	report, err := e.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Can't resume DAG: %s", err)
+	}
+	if !reflect.DeepEqual(ran, []string{"3"}) {
+		t.Fatalf("Expected resumed run to run %v but got %v", []string{"3"}, ran)
+	}
+
+	for _, id := range []string{"1", "2", "4"} {
+		checkStatus(t, report, id, executor.Succeeded)
+		if !report.Result(id).Restored {
+			t.Fatalf("Vertex %s expected to be restored but got %s", id, report.Result(id))
+		}
+	}
+	checkStatus(t, report, "3", executor.Succeeded)
+	if string(report.Result("3").Output) != "123" {
+		t.Fatalf("Expected vertex 3 output to be %q but got %q", "123", report.Result("3").Output)
+	}
+
+	expected := "2: succeeded: restored from checkpoint"
+	if report.Result("2").String() != expected {
+		t.Fatalf("Result stringer expected to be %q but got %q", expected, report.Result("2").String())
+	}
+}
+
+func TestExecutor_Run_ResumeRerunsDescendants(t *testing.T) {
+	dag1 := newTestDAG(t,
+		[]string{"1", "2"},
+		[][2]string{{"1", "2"}},
+	)
+	store := executor.NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint"))
+
+	// Vertex 2 completed once, but its parent did not.
+	err := store.Save("2", nil)
+	if err != nil {
+		t.Fatalf("Can't save checkpoint: %s", err)
+	}
+
+	var ran []string
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		ran = append(ran, v.ID)
+		return nil
+	})
+	e.Workers = 1
+	e.Checkpoint = store
+
+	_, err = e.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Can't run DAG: %s", err)
+	}
+	if !reflect.DeepEqual(ran, []string{"1", "2"}) {
+		t.Fatalf("Expected run to run %v but got %v", []string{"1", "2"}, ran)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+/*
+Package executor runs a task for every vertex of a dag.DAG, honouring the
+graph edges: a vertex only runs once all its parents have finished.
+
+Independent vertices run in parallel, up to a configurable number of
+workers, and the whole run can be cancelled through its context. A failure
+policy decides whether a failed vertex stops the whole run, only skips its
+descendants, or doesn't stop anything.
+
+Each vertex can also carry its own Options, stored in its metadata with
+SetOptions, to retry its task with an exponential backoff and to limit how
+long each attempt can run.
+
+Tasks can record an output with SetOutput, which their descendants read with
+Output. With a CheckpointStore, such as a FileCheckpointStore, every
+succeeded vertex and its output are saved as the run goes, and a later run
+of the same graph only runs the vertices not completed yet.
+
+Example
+
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		fmt.Println("running", v.ID)
+		return nil
+	})
+	e.Workers = 4
+	e.FailurePolicy = executor.SkipDownstream
+
+	report, err := e.Run(context.Background())
+	if err != nil {
+		fmt.Println("run failed:", err)
+	}
+	for _, result := range report.Results {
+		fmt.Println(result.Vertex.ID, result.Status)
+	}
+*/
+package executor
@@ -0,0 +1,330 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package executor
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/goombaio/dag"
+)
+
+// FailurePolicy decides what a run does when a vertex task fails.
+type FailurePolicy int
+
+const (
+	// FailFast stops the run on the first failure: no more tasks are started
+	// and the running ones are cancelled.
+	FailFast FailurePolicy = iota
+
+	// SkipDownstream skips every descendant of a failed vertex, but keeps
+	// running the vertices that don't depend on it.
+	SkipDownstream
+
+	// ContinueAll runs every vertex, even when some of its ancestors failed.
+	ContinueAll
+)
+
+// String implements stringer interface.
+func (p FailurePolicy) String() string {
+	switch p {
+	case FailFast:
+		return "fail-fast"
+	case SkipDownstream:
+		return "skip-downstream"
+	case ContinueAll:
+		return "continue-all"
+	}
+
+	return fmt.Sprintf("FailurePolicy(%d)", int(p))
+}
+
+// Task is the work run for each vertex of the graph.
+type Task func(ctx context.Context, vertex *dag.Vertex) error
+
+// Executor runs a task for every vertex of a graph, each one once all its
+// parents have finished.
+type Executor struct {
+	// Workers is the maximum number of tasks running at the same time. If
+	// it is zero or negative, the number of CPUs is used.
+	Workers int
+
+	// FailurePolicy decides what happens when a task fails. It defaults to
+	// FailFast.
+	FailurePolicy FailurePolicy
+
+	// Checkpoint, if not nil, records every succeeded vertex so a later run
+	// can resume from there.
+	Checkpoint CheckpointStore
+
+	dag  *dag.DAG
+	task Task
+}
+
+// NewExecutor creates a new executor running the given task over the
+// vertices of a graph.
+func NewExecutor(d *dag.DAG, task Task) *Executor {
+	e := &Executor{
+		dag:  d,
+		task: task,
+	}
+
+	return e
+}
+
+// Run executes the task for every vertex of the graph and waits for all of
+// them to finish.
+//
+// Vertices are started in topological order as soon as all their parents
+// have finished, with at most Workers of them running at once. What happens
+// when a task fails depends on the FailurePolicy. When ctx is cancelled, no
+// more tasks are started and the context of the running ones is cancelled.
+//
+// With a Checkpoint store, the vertices completed by a previous run are not
+// run again but restored with their output, as long as all their parents
+// are restored too. Only the rest of the graph runs.
+//
+// The report holds a result for every vertex, even when the returned error,
+// which describes the first failure or the context error, is not nil.
+func (e *Executor) Run(ctx context.Context) (*Report, error) {
+	vertices, err := e.dag.TopologicalSortStable()
+	if err != nil {
+		return nil, err
+	}
+
+	completed := make(map[string][]byte)
+	if e.Checkpoint != nil {
+		completed, err = e.Checkpoint.Load()
+		if err != nil {
+			return nil, fmt.Errorf("can't load checkpoint: %w", err)
+		}
+	}
+
+	report := newReport(vertices)
+	outputs := newOutputs()
+
+	// pending holds the number of parents not finished yet of each vertex,
+	// by its position in the topological order.
+	position := make(map[*dag.Vertex]int, len(vertices))
+	for i, vertex := range vertices {
+		position[vertex] = i
+	}
+	pending := make([]int, len(vertices))
+	ready := &positionHeap{}
+	for i, vertex := range vertices {
+		for _, parent := range vertex.Parents.Values() {
+			j, found := position[parent.(*dag.Vertex)]
+			if found && !report.Results[j].Restored {
+				pending[i]++
+			}
+		}
+
+		output, found := completed[vertex.ID]
+		switch {
+		case found && pending[i] == 0:
+			result := report.Results[i]
+			result.Status = Succeeded
+			result.Restored = true
+			result.Output = output
+			outputs.set(vertex, output)
+		case pending[i] == 0:
+			heap.Push(ready, i)
+		}
+	}
+
+	workers := e.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var runErr error
+	done := make(chan *Result)
+	running := 0
+
+	for {
+		for running < workers && ready.Len() > 0 && runCtx.Err() == nil {
+			result := report.Results[heap.Pop(ready).(int)]
+			running++
+			go e.runTask(runCtx, outputs, result, done)
+		}
+		if running == 0 {
+			break
+		}
+
+		result := <-done
+		running--
+
+		if result.Status == Failed && runErr == nil {
+			runErr = fmt.Errorf("vertex %s failed: %w", result.Vertex.ID, result.Err)
+		}
+		if result.Status == Succeeded && e.Checkpoint != nil {
+			err := e.Checkpoint.Save(result.Vertex.ID, result.Output)
+			if err != nil && runErr == nil {
+				runErr = fmt.Errorf("can't checkpoint vertex %s: %w", result.Vertex.ID, err)
+			}
+		}
+
+		switch {
+		case result.Status == Succeeded, result.Status == Failed && e.FailurePolicy == ContinueAll:
+			for _, child := range result.Vertex.Children.Values() {
+				i, found := position[child.(*dag.Vertex)]
+				if !found {
+					continue
+				}
+				pending[i]--
+				if pending[i] == 0 {
+					heap.Push(ready, i)
+				}
+			}
+		case result.Status == Failed && e.FailurePolicy == SkipDownstream:
+			skipDescendants(report, result.Vertex)
+		case result.Status == Failed:
+			cancel()
+		}
+	}
+
+	if runErr == nil {
+		runErr = ctx.Err()
+	}
+
+	return report, runErr
+}
+
+// skipDescendants marks every descendant of a failed vertex as skipped,
+// unless already skipped by an earlier failure.
+func skipDescendants(report *Report, failed *dag.Vertex) {
+	queue := []*dag.Vertex{failed}
+	for len(queue) > 0 {
+		vertex := queue[0]
+		queue = queue[1:]
+
+		for _, child := range vertex.Children.Values() {
+			result := report.Result(child.(*dag.Vertex).ID)
+			if result == nil || result.Status == Skipped {
+				continue
+			}
+			result.Status = Skipped
+			result.Cause = failed
+			queue = append(queue, result.Vertex)
+		}
+	}
+}
+
+// runTask runs the task of a single vertex, retrying it as configured in its
+// options, fills its result and sends it to done.
+func (e *Executor) runTask(ctx context.Context, outputs *outputs, result *Result, done chan<- *Result) {
+	options := GetOptions(result.Vertex)
+	if options == nil {
+		options = &Options{}
+	}
+
+	ctx = context.WithValue(ctx, taskKey{}, &taskContext{e.dag, outputs, result.Vertex})
+
+	var err error
+
+	result.Start = time.Now()
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && !sleep(ctx, options.Retry.jitteredBackoff(attempt)) {
+			break
+		}
+
+		outputs.set(result.Vertex, nil)
+		err = e.runAttempt(ctx, result, options)
+		if err == nil || ctx.Err() != nil || !options.Retry.retryable(attempt, err) {
+			break
+		}
+	}
+	result.End = time.Now()
+
+	switch {
+	case err == nil:
+		result.Status = Succeeded
+		result.Output, _ = outputs.get(result.Vertex)
+	case ctx.Err() != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)):
+		result.Status = Cancelled
+	default:
+		result.Status = Failed
+	}
+	result.Err = err
+
+	done <- result
+}
+
+// runAttempt runs the task of a vertex once, within its timeout, and records
+// the attempt in its result.
+func (e *Executor) runAttempt(ctx context.Context, result *Result, options *Options) error {
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	attempt := &Attempt{Start: time.Now()}
+	attempt.Err = e.callTask(ctx, result.Vertex)
+	attempt.End = time.Now()
+	result.Attempts = append(result.Attempts, attempt)
+
+	return attempt.Err
+}
+
+// sleep waits for the given duration, and return false if ctx is done
+// before.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// callTask calls the task, turning a panic into an error.
+func (e *Executor) callTask(ctx context.Context, vertex *dag.Vertex) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task panicked: %v", r)
+		}
+	}()
+
+	return e.task(ctx, vertex)
+}
+
+// positionHeap is a min-heap of vertex positions in the topological order.
+type positionHeap []int
+
+func (h positionHeap) Len() int            { return len(h) }
+func (h positionHeap) Less(i, j int) bool  { return h[i] < h[j] }
+func (h positionHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *positionHeap) Push(x interface{}) { *h = append(*h, x.(int)) }
+func (h *positionHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+
+	return x
+}
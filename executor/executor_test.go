@@ -0,0 +1,315 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goombaio/dag"
+	"github.com/goombaio/dag/executor"
+)
+
+// newTestDAG builds a DAG with a vertex for each id, added in order, and the
+// given edges as pairs of tail and head ids.
+func newTestDAG(t *testing.T, ids []string, edges [][2]string) *dag.DAG {
+	dag1 := dag.NewDAG()
+	vertices := make(map[string]*dag.Vertex, len(ids))
+
+	for _, id := range ids {
+		vertex := dag.NewVertex(id, nil)
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+		vertices[id] = vertex
+	}
+
+	for _, edge := range edges {
+		err := dag1.AddEdge(vertices[edge[0]], vertices[edge[1]])
+		if err != nil {
+			t.Fatalf("Can't add edge (%s,%s) to DAG: %s", edge[0], edge[1], err)
+		}
+	}
+
+	return dag1
+}
+
+// checkStatus fails the test if the vertex result hasn't the expected status.
+func checkStatus(t *testing.T, report *executor.Report, id string, expected executor.Status) {
+	result := report.Result(id)
+	if result == nil {
+		t.Fatalf("Expected a result for vertex %s", id)
+	}
+	if result.Status != expected {
+		t.Fatalf("Expected vertex %s to be %s but got %s", id, expected, result)
+	}
+}
+
+func TestExecutor_Run(t *testing.T) {
+	dag1 := newTestDAG(t,
+		[]string{"1", "2", "3", "4", "5"},
+		[][2]string{{"1", "2"}, {"1", "3"}, {"2", "4"}, {"3", "4"}, {"4", "5"}},
+	)
+
+	var mu sync.Mutex
+	finished := make(map[string]bool)
+
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, parent := range v.Parents.Values() {
+			if !finished[parent.(*dag.Vertex).ID] {
+				t.Errorf("Vertex %s started before its parent %s finished", v.ID, parent.(*dag.Vertex).ID)
+			}
+		}
+		finished[v.ID] = true
+
+		return nil
+	})
+
+	report, err := e.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Can't run DAG: %s", err)
+	}
+
+	if !report.Succeeded() {
+		t.Fatalf("Expected every vertex to succeed but got %v", report.Results)
+	}
+	if len(finished) != 5 {
+		t.Fatalf("Expected 5 vertices to run but got %d", len(finished))
+	}
+	for _, result := range report.Results {
+		if result.Start.IsZero() || result.End.Before(result.Start) {
+			t.Fatalf("Unexpected start and end times for vertex %s", result.Vertex.ID)
+		}
+	}
+}
+
+func TestExecutor_Run_Workers(t *testing.T) {
+	dag1 := newTestDAG(t,
+		[]string{"1", "2", "3", "4", "5", "6"},
+		nil,
+	)
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		return nil
+	})
+	e.Workers = 2
+
+	_, err := e.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Can't run DAG: %s", err)
+	}
+
+	if maxRunning > 2 {
+		t.Fatalf("Expected at most 2 tasks running at once but got %d", maxRunning)
+	}
+}
+
+func TestExecutor_Run_Failure(t *testing.T) {
+	dag1 := newTestDAG(t,
+		[]string{"1", "2", "3"},
+		[][2]string{{"1", "2"}, {"2", "3"}},
+	)
+
+	errBoom := errors.New("boom")
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		if v.ID == "2" {
+			return errBoom
+		}
+		return nil
+	})
+
+	report, err := e.Run(context.Background())
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("Expected run error to wrap %q but got %v", errBoom, err)
+	}
+	expectedMsg := "vertex 2 failed: boom"
+	if err.Error() != expectedMsg {
+		t.Fatalf("Expected error message to be %q but got %q", expectedMsg, err.Error())
+	}
+
+	checkStatus(t, report, "1", executor.Succeeded)
+	checkStatus(t, report, "2", executor.Failed)
+	checkStatus(t, report, "3", executor.Cancelled)
+	if !report.Result("3").Start.IsZero() {
+		t.Fatalf("Vertex 3 expected to never start")
+	}
+}
+
+func TestExecutor_Run_FailureCancelsRunning(t *testing.T) {
+	dag1 := newTestDAG(t,
+		[]string{"slow", "fail"},
+		nil,
+	)
+
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		if v.ID == "fail" {
+			return errors.New("boom")
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	e.Workers = 2
+
+	report, err := e.Run(context.Background())
+	if err == nil {
+		t.Fatalf("Vertex fails, Run should fail but it doesn't")
+	}
+
+	checkStatus(t, report, "slow", executor.Cancelled)
+	checkStatus(t, report, "fail", executor.Failed)
+}
+
+func TestExecutor_Run_Cancelled(t *testing.T) {
+	dag1 := newTestDAG(t,
+		[]string{"1", "2"},
+		[][2]string{{"1", "2"}},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		t.Errorf("Run was cancelled, vertex %s should not run", v.ID)
+		return nil
+	})
+
+	report, err := e.Run(ctx)
+	if err != context.Canceled {
+		t.Fatalf("Expected run error to be %q but got %v", context.Canceled, err)
+	}
+
+	checkStatus(t, report, "1", executor.Cancelled)
+	checkStatus(t, report, "2", executor.Cancelled)
+}
+
+func TestExecutor_Run_Panic(t *testing.T) {
+	dag1 := newTestDAG(t,
+		[]string{"1"},
+		nil,
+	)
+
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		panic("boom")
+	})
+
+	report, err := e.Run(context.Background())
+	if err == nil {
+		t.Fatalf("Task panics, Run should fail but it doesn't")
+	}
+
+	checkStatus(t, report, "1", executor.Failed)
+	expectedMsg := "task panicked: boom"
+	if report.Result("1").Err.Error() != expectedMsg {
+		t.Fatalf("Expected error message to be %q but got %q", expectedMsg, report.Result("1").Err)
+	}
+}
+
+func TestExecutor_Run_SkipDownstream(t *testing.T) {
+	dag1 := newTestDAG(t,
+		[]string{"1", "2", "3", "4", "5", "6"},
+		[][2]string{{"1", "2"}, {"2", "3"}, {"1", "4"}, {"4", "5"}, {"3", "6"}, {"5", "6"}},
+	)
+
+	var mu sync.Mutex
+	ran := make(map[string]bool)
+
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		mu.Lock()
+		ran[v.ID] = true
+		mu.Unlock()
+
+		if v.ID == "2" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	e.FailurePolicy = executor.SkipDownstream
+
+	report, err := e.Run(context.Background())
+	if err == nil {
+		t.Fatalf("Vertex fails, Run should fail but it doesn't")
+	}
+
+	checkStatus(t, report, "1", executor.Succeeded)
+	checkStatus(t, report, "2", executor.Failed)
+	checkStatus(t, report, "3", executor.Skipped)
+	checkStatus(t, report, "4", executor.Succeeded)
+	checkStatus(t, report, "5", executor.Succeeded)
+	checkStatus(t, report, "6", executor.Skipped)
+
+	for _, id := range []string{"3", "6"} {
+		if ran[id] {
+			t.Fatalf("Vertex %s expected to be skipped but it ran", id)
+		}
+		if report.Result(id).Cause.ID != "2" {
+			t.Fatalf("Vertex %s expected to be skipped because of vertex 2 but got %s", id, report.Result(id))
+		}
+	}
+
+	expected := "6: skipped: upstream vertex 2 failed"
+	if report.Result("6").String() != expected {
+		t.Fatalf("Result stringer expected to be %q but got %q", expected, report.Result("6").String())
+	}
+}
+
+func TestExecutor_Run_ContinueAll(t *testing.T) {
+	dag1 := newTestDAG(t,
+		[]string{"1", "2", "3"},
+		[][2]string{{"1", "2"}, {"2", "3"}},
+	)
+
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		if v.ID != "3" {
+			return errors.New("boom " + v.ID)
+		}
+		return nil
+	})
+	e.FailurePolicy = executor.ContinueAll
+
+	report, err := e.Run(context.Background())
+	expectedMsg := "vertex 1 failed: boom 1"
+	if err == nil || err.Error() != expectedMsg {
+		t.Fatalf("Expected run error to be %q but got %v", expectedMsg, err)
+	}
+
+	checkStatus(t, report, "1", executor.Failed)
+	checkStatus(t, report, "2", executor.Failed)
+	checkStatus(t, report, "3", executor.Succeeded)
+}
@@ -0,0 +1,136 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package executor
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/goombaio/dag"
+)
+
+// OptionsKey is the vertex metadata key holding its task options.
+const OptionsKey = "executor.options"
+
+// Options configures how the task of a single vertex is run.
+type Options struct {
+	// Retry decides if and when a failed task is run again.
+	Retry RetryPolicy
+
+	// Timeout limits how long each attempt of the task can run. The context
+	// passed to the task is cancelled once it expires. Zero means no limit.
+	Timeout time.Duration
+}
+
+// RetryPolicy configures the retries of a failed task, waiting longer
+// before each new attempt.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times the task is run, counting
+	// the first one. Values lower than 2 disable retries.
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the second attempt.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait between attempts. Zero means no cap.
+	MaxBackoff time.Duration
+
+	// Multiplier grows the wait after each attempt. Values lower than 1
+	// default to 2.
+	Multiplier float64
+
+	// Jitter randomizes each wait by up to this fraction of it, in both
+	// directions, so tasks failing together don't retry together. It is
+	// clamped between 0 and 1.
+	Jitter float64
+
+	// Retryable reports if an error is worth retrying. If nil, every error
+	// is.
+	Retryable func(err error) bool
+}
+
+// Backoff return the wait before the given attempt, counting from 1 for the
+// first one, without jitter.
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt <= 1 {
+		return 0
+	}
+
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-2))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+	// float64(math.MaxInt64) rounds up to 1<<63, which doesn't fit either.
+	if backoff >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+
+	return time.Duration(backoff)
+}
+
+// jitteredBackoff return the wait before the given attempt, with jitter.
+func (p *RetryPolicy) jitteredBackoff(attempt int) time.Duration {
+	backoff := p.Backoff(attempt)
+
+	jitter := math.Max(0, math.Min(1, p.Jitter))
+	if jitter == 0 {
+		return backoff
+	}
+
+	// Jitter may push a backoff capped near the maximum duration past it.
+	jittered := float64(backoff) * (1 + jitter*(2*rand.Float64()-1))
+	if jittered >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+
+	return time.Duration(jittered)
+}
+
+// retryable return if a failed attempt should be followed by another one.
+func (p *RetryPolicy) retryable(attempt int, err error) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+	if p.Retryable == nil {
+		return true
+	}
+
+	return p.Retryable(err)
+}
+
+// SetOptions attaches task options to a vertex, in its metadata.
+func SetOptions(vertex *dag.Vertex, options *Options) {
+	if vertex.Metadata == nil {
+		vertex.Metadata = make(map[string]interface{})
+	}
+
+	vertex.Metadata[OptionsKey] = options
+}
+
+// GetOptions return the task options attached to a vertex, or nil if it has
+// none.
+func GetOptions(vertex *dag.Vertex) *Options {
+	options, _ := vertex.Metadata[OptionsKey].(*Options)
+
+	return options
+}
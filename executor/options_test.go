@@ -0,0 +1,262 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goombaio/dag"
+	"github.com/goombaio/dag/executor"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := &executor.RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	}
+
+	expected := []time.Duration{
+		0,
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+	}
+	for i, expectedBackoff := range expected {
+		backoff := policy.Backoff(i + 1)
+		if backoff != expectedBackoff {
+			t.Fatalf("Expected backoff before attempt %d to be %s but got %s", i+1, expectedBackoff, backoff)
+		}
+	}
+
+	policy.Multiplier = 3
+	policy.MaxBackoff = 0
+	backoff := policy.Backoff(4)
+	if backoff != 900*time.Millisecond {
+		t.Fatalf("Expected backoff before attempt 4 to be %s but got %s", 900*time.Millisecond, backoff)
+	}
+
+	backoff = policy.Backoff(100)
+	if backoff != time.Duration(math.MaxInt64) {
+		t.Fatalf("Expected backoff before attempt 100 to be %s but got %s", time.Duration(math.MaxInt64), backoff)
+	}
+}
+
+func TestExecutor_Run_RetryMaxBackoff(t *testing.T) {
+	// Many vertices wait at once, so an overflowing wait would show up in
+	// at least one of them.
+	var ids []string
+	for i := 0; i < 32; i++ {
+		ids = append(ids, fmt.Sprint(i))
+	}
+	dag1 := newTestDAG(t, ids, nil)
+	for _, vertex := range dag1.Vertices() {
+		executor.SetOptions(vertex, &executor.Options{
+			Retry: executor.RetryPolicy{
+				MaxAttempts:    100,
+				InitialBackoff: time.Nanosecond,
+				MaxBackoff:     time.Duration(math.MaxInt64),
+				Multiplier:     1e30,
+				Jitter:         1,
+			},
+		})
+	}
+
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		return errors.New("unavailable")
+	})
+	e.Workers = len(ids)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	report, err := e.Run(ctx)
+	if err == nil {
+		t.Fatalf("Tasks fail, Run should fail but it doesn't")
+	}
+
+	// The third attempt waits for the longest duration, jitter or not.
+	for _, id := range ids {
+		if attempts := len(report.Result(id).Attempts); attempts != 2 {
+			t.Fatalf("Expected vertex %s to have 2 attempts but got %d", id, attempts)
+		}
+	}
+}
+
+func TestOptions(t *testing.T) {
+	v := dag.NewVertex("1", nil)
+
+	if executor.GetOptions(v) != nil {
+		t.Fatalf("Vertex options expected to be nil")
+	}
+
+	options := &executor.Options{Timeout: time.Second}
+	executor.SetOptions(v, options)
+	if executor.GetOptions(v) != options {
+		t.Fatalf("Vertex options expected to be %v but got %v", options, executor.GetOptions(v))
+	}
+	if v.Metadata[executor.OptionsKey] != options {
+		t.Fatalf("Vertex options expected to be stored in its metadata")
+	}
+}
+
+func TestExecutor_Run_Retry(t *testing.T) {
+	dag1 := newTestDAG(t,
+		[]string{"flaky", "next"},
+		[][2]string{{"flaky", "next"}},
+	)
+	flaky, _ := dag1.GetVertex("flaky")
+	executor.SetOptions(flaky, &executor.Options{
+		Retry: executor.RetryPolicy{
+			MaxAttempts:    5,
+			InitialBackoff: time.Millisecond,
+			Jitter:         0.5,
+		},
+	})
+
+	var mu sync.Mutex
+	calls := 0
+
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		if v.ID != "flaky" {
+			return nil
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls < 3 {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+
+	report, err := e.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Can't run DAG: %s", err)
+	}
+
+	checkStatus(t, report, "flaky", executor.Succeeded)
+	checkStatus(t, report, "next", executor.Succeeded)
+
+	attempts := report.Result("flaky").Attempts
+	if len(attempts) != 3 {
+		t.Fatalf("Expected 3 attempts but got %d", len(attempts))
+	}
+	for i, attempt := range attempts[:2] {
+		if attempt.Err == nil || attempt.Err.Error() != "unavailable" {
+			t.Fatalf("Expected attempt %d to fail but got %v", i+1, attempt.Err)
+		}
+	}
+	if attempts[2].Err != nil {
+		t.Fatalf("Expected attempt 3 to succeed but got %s", attempts[2].Err)
+	}
+	if attempts[1].Start.Before(attempts[0].End) {
+		t.Fatalf("Expected attempt 2 to start after attempt 1 ended")
+	}
+	if len(report.Result("next").Attempts) != 1 {
+		t.Fatalf("Expected 1 attempt but got %d", len(report.Result("next").Attempts))
+	}
+}
+
+func TestExecutor_Run_RetryExhausted(t *testing.T) {
+	dag1 := newTestDAG(t,
+		[]string{"1"},
+		nil,
+	)
+	vertex1, _ := dag1.GetVertex("1")
+	executor.SetOptions(vertex1, &executor.Options{
+		Retry: executor.RetryPolicy{MaxAttempts: 3},
+	})
+
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		return errors.New("boom")
+	})
+
+	report, err := e.Run(context.Background())
+	if err == nil {
+		t.Fatalf("Vertex fails, Run should fail but it doesn't")
+	}
+
+	checkStatus(t, report, "1", executor.Failed)
+	if len(report.Result("1").Attempts) != 3 {
+		t.Fatalf("Expected 3 attempts but got %d", len(report.Result("1").Attempts))
+	}
+}
+
+func TestExecutor_Run_NotRetryable(t *testing.T) {
+	dag1 := newTestDAG(t,
+		[]string{"1"},
+		nil,
+	)
+	errFatal := errors.New("fatal")
+	vertex1, _ := dag1.GetVertex("1")
+	executor.SetOptions(vertex1, &executor.Options{
+		Retry: executor.RetryPolicy{
+			MaxAttempts: 3,
+			Retryable: func(err error) bool {
+				return !errors.Is(err, errFatal)
+			},
+		},
+	})
+
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		return errFatal
+	})
+
+	report, err := e.Run(context.Background())
+	if !errors.Is(err, errFatal) {
+		t.Fatalf("Expected run error to wrap %q but got %v", errFatal, err)
+	}
+	if len(report.Result("1").Attempts) != 1 {
+		t.Fatalf("Expected 1 attempt but got %d", len(report.Result("1").Attempts))
+	}
+}
+
+func TestExecutor_Run_Timeout(t *testing.T) {
+	dag1 := newTestDAG(t,
+		[]string{"slow"},
+		nil,
+	)
+	slow, _ := dag1.GetVertex("slow")
+	executor.SetOptions(slow, &executor.Options{
+		Retry:   executor.RetryPolicy{MaxAttempts: 2},
+		Timeout: 10 * time.Millisecond,
+	})
+
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report, err := e.Run(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected run error to wrap %q but got %v", context.DeadlineExceeded, err)
+	}
+
+	checkStatus(t, report, "slow", executor.Failed)
+	if len(report.Result("slow").Attempts) != 2 {
+		t.Fatalf("Expected 2 attempts but got %d", len(report.Result("slow").Attempts))
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package executor
+
+import (
+	"context"
+	"sync"
+
+	"github.com/goombaio/dag"
+)
+
+// outputs holds the outputs recorded by the vertex tasks of a run.
+type outputs struct {
+	mu   sync.Mutex
+	data map[*dag.Vertex][]byte
+}
+
+// newOutputs creates a new empty set of outputs.
+func newOutputs() *outputs {
+	o := &outputs{
+		data: make(map[*dag.Vertex][]byte),
+	}
+
+	return o
+}
+
+// set records the output of a vertex, or forgets it if output is nil.
+func (o *outputs) set(vertex *dag.Vertex, output []byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if output == nil {
+		delete(o.data, vertex)
+		return
+	}
+	o.data[vertex] = output
+}
+
+// get return the output of a vertex.
+func (o *outputs) get(vertex *dag.Vertex) ([]byte, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	output, found := o.data[vertex]
+
+	return output, found
+}
+
+// taskKey is the context key of the taskContext of a running task.
+type taskKey struct{}
+
+// taskContext identifies a running task within its run.
+type taskContext struct {
+	dag     *dag.DAG
+	outputs *outputs
+	vertex  *dag.Vertex
+}
+
+// SetOutput records the output of the vertex task running with ctx.
+//
+// The output of a succeeded task is kept in its Result, saved in the
+// checkpoint store and available to the tasks of its descendants through
+// Output. If the task is retried, only the output set by the last attempt is
+// kept. Calling SetOutput with a context not passed to a task does nothing.
+func SetOutput(ctx context.Context, output []byte) {
+	tc, ok := ctx.Value(taskKey{}).(*taskContext)
+	if !ok {
+		return
+	}
+
+	tc.outputs.set(tc.vertex, output)
+}
+
+// Output return the output recorded by the task of a vertex in the same run
+// as the task running with ctx, or restored from a checkpoint.
+func Output(ctx context.Context, vertex *dag.Vertex) ([]byte, bool) {
+	tc, ok := ctx.Value(taskKey{}).(*taskContext)
+	if !ok {
+		return nil, false
+	}
+
+	return tc.outputs.get(vertex)
+}
+
+// InEdges return the edges from the parents of the vertex whose task is
+// running with ctx, with their label, weight and attributes, in the order
+// of its parents.
+func InEdges(ctx context.Context) []*dag.Edge {
+	tc, ok := ctx.Value(taskKey{}).(*taskContext)
+	if !ok {
+		return nil
+	}
+
+	edges, _ := tc.dag.InEdges(tc.vertex)
+
+	return edges
+}
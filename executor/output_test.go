@@ -0,0 +1,138 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/goombaio/dag"
+	"github.com/goombaio/dag/executor"
+)
+
+func TestOutput(t *testing.T) {
+	dag1 := newTestDAG(t,
+		[]string{"1", "2"},
+		[][2]string{{"1", "2"}},
+	)
+	vertex1, _ := dag1.GetVertex("1")
+
+	var input []byte
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		if v.ID == "1" {
+			executor.SetOutput(ctx, []byte("hello"))
+			return nil
+		}
+		input, _ = executor.Output(ctx, vertex1)
+		return nil
+	})
+
+	report, err := e.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Can't run DAG: %s", err)
+	}
+
+	if string(input) != "hello" {
+		t.Fatalf("Expected vertex 2 input to be %q but got %q", "hello", input)
+	}
+	if string(report.Result("1").Output) != "hello" {
+		t.Fatalf("Expected vertex 1 output to be %q but got %q", "hello", report.Result("1").Output)
+	}
+	if report.Result("2").Output != nil {
+		t.Fatalf("Expected vertex 2 output to be nil but got %q", report.Result("2").Output)
+	}
+}
+
+func TestOutput_Retry(t *testing.T) {
+	dag1 := newTestDAG(t,
+		[]string{"1"},
+		nil,
+	)
+	vertex1, _ := dag1.GetVertex("1")
+	executor.SetOptions(vertex1, &executor.Options{
+		Retry: executor.RetryPolicy{MaxAttempts: 2},
+	})
+
+	attempt := 0
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		attempt++
+		if attempt == 1 {
+			executor.SetOutput(ctx, []byte("partial"))
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	report, err := e.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Can't run DAG: %s", err)
+	}
+	if report.Result("1").Output != nil {
+		t.Fatalf("Expected output of the failed attempt to be dropped but got %q", report.Result("1").Output)
+	}
+}
+
+func TestOutput_OutsideTask(t *testing.T) {
+	ctx := context.Background()
+
+	executor.SetOutput(ctx, []byte("ignored"))
+
+	_, found := executor.Output(ctx, dag.NewVertex("1", nil))
+	if found {
+		t.Fatalf("Output expected to not be found outside a task")
+	}
+
+	if edges := executor.InEdges(ctx); edges != nil {
+		t.Fatalf("Expected no edges outside a task but got %v", edges)
+	}
+}
+
+func TestInEdges(t *testing.T) {
+	dag1 := newTestDAG(t,
+		[]string{"raw", "lookup", "joined"},
+		[][2]string{{"raw", "joined"}},
+	)
+	lookup, _ := dag1.GetVertex("lookup")
+	joined, _ := dag1.GetVertex("joined")
+	err := dag1.AddEdgeWithAttrs(lookup, joined, "enriches", 1, map[string]interface{}{"key": "id"})
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+
+	var labels []string
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		if v != joined {
+			return nil
+		}
+		for _, edge := range executor.InEdges(ctx) {
+			labels = append(labels, edge.Tail.ID+":"+edge.Label)
+		}
+		return nil
+	})
+
+	_, err = e.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Can't run DAG: %s", err)
+	}
+
+	expected := []string{"raw:", "lookup:enriches"}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Fatalf("Expected edges of vertex joined to be %v but got %v", expected, labels)
+	}
+}
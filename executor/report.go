@@ -0,0 +1,157 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goombaio/dag"
+)
+
+// Status is the outcome of a vertex in a run.
+type Status int
+
+const (
+	// Cancelled means the vertex task was not run, or was interrupted,
+	// because the run was cancelled.
+	Cancelled Status = iota
+
+	// Succeeded means the vertex task returned no error.
+	Succeeded
+
+	// Failed means the vertex task returned an error.
+	Failed
+
+	// Skipped means the vertex task was not run because an upstream vertex
+	// failed.
+	Skipped
+)
+
+// String implements stringer interface.
+func (s Status) String() string {
+	switch s {
+	case Cancelled:
+		return "cancelled"
+	case Succeeded:
+		return "succeeded"
+	case Failed:
+		return "failed"
+	case Skipped:
+		return "skipped"
+	}
+
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// Attempt is a single run of a vertex task.
+type Attempt struct {
+	Start time.Time
+	End   time.Time
+
+	// Err is the error returned by the task in this attempt, if any.
+	Err error
+}
+
+// Result is the outcome of running a single vertex.
+type Result struct {
+	Vertex *dag.Vertex
+	Status Status
+
+	// Err is the error returned by the last attempt of the task, if any.
+	Err error
+
+	// Attempts holds every run of the task, in order. It has more than one
+	// entry when the task was retried.
+	Attempts []*Attempt
+
+	// Cause is the failed upstream vertex for a skipped vertex.
+	Cause *dag.Vertex
+
+	// Output is the output set by a succeeded task with SetOutput.
+	Output []byte
+
+	// Restored tells the vertex was not run because a previous run already
+	// completed it, as recorded in the checkpoint store.
+	Restored bool
+
+	// Start and End span all the attempts. They are the zero time if the
+	// task was never started.
+	Start time.Time
+	End   time.Time
+}
+
+// Duration return how long the vertex task took to run.
+func (r *Result) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// String implements stringer interface.
+func (r *Result) String() string {
+	if r.Restored {
+		return fmt.Sprintf("%s: %s: restored from checkpoint", r.Vertex.ID, r.Status)
+	}
+	if r.Cause != nil {
+		return fmt.Sprintf("%s: %s: upstream vertex %s failed", r.Vertex.ID, r.Status, r.Cause.ID)
+	}
+	if r.Err != nil {
+		return fmt.Sprintf("%s: %s: %s", r.Vertex.ID, r.Status, r.Err)
+	}
+
+	return fmt.Sprintf("%s: %s", r.Vertex.ID, r.Status)
+}
+
+// Report holds the results of a run.
+type Report struct {
+	// Results holds a result for every vertex of the graph in topological
+	// order.
+	Results []*Result
+
+	index map[string]*Result
+}
+
+// newReport creates a report with a cancelled result for each vertex.
+func newReport(vertices []*dag.Vertex) *Report {
+	r := &Report{
+		Results: make([]*Result, len(vertices)),
+		index:   make(map[string]*Result, len(vertices)),
+	}
+
+	for i, vertex := range vertices {
+		r.Results[i] = &Result{Vertex: vertex, Status: Cancelled}
+		r.index[vertex.ID] = r.Results[i]
+	}
+
+	return r
+}
+
+// Result return the result of a vertex given its ID, or nil if the vertex
+// was not part of the run.
+func (r *Report) Result(id string) *Result {
+	return r.index[id]
+}
+
+// Succeeded return if every vertex of the run succeeded.
+func (r *Report) Succeeded() bool {
+	for _, result := range r.Results {
+		if result.Status != Succeeded {
+			return false
+		}
+	}
+
+	return true
+}
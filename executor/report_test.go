@@ -0,0 +1,90 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/goombaio/dag"
+	"github.com/goombaio/dag/executor"
+)
+
+func TestStatus_String(t *testing.T) {
+	expected := map[executor.Status]string{
+		executor.Cancelled:   "cancelled",
+		executor.Succeeded:   "succeeded",
+		executor.Failed:      "failed",
+		executor.Status(100): "Status(100)",
+	}
+
+	for status, expectedStr := range expected {
+		if status.String() != expectedStr {
+			t.Fatalf("Status stringer expected to be %q but got %q", expectedStr, status.String())
+		}
+	}
+}
+
+func TestFailurePolicy_String(t *testing.T) {
+	expected := map[executor.FailurePolicy]string{
+		executor.FailFast:           "fail-fast",
+		executor.SkipDownstream:     "skip-downstream",
+		executor.ContinueAll:        "continue-all",
+		executor.FailurePolicy(100): "FailurePolicy(100)",
+	}
+
+	for policy, expectedStr := range expected {
+		if policy.String() != expectedStr {
+			t.Fatalf("FailurePolicy stringer expected to be %q but got %q", expectedStr, policy.String())
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	dag1 := newTestDAG(t,
+		[]string{"b", "a"},
+		[][2]string{{"a", "b"}},
+	)
+
+	e := executor.NewExecutor(dag1, func(ctx context.Context, v *dag.Vertex) error {
+		if v.ID == "b" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	report, _ := e.Run(context.Background())
+
+	if report.Succeeded() {
+		t.Fatalf("Report expected to not succeed")
+	}
+	if len(report.Results) != 2 || report.Results[0].Vertex.ID != "a" {
+		t.Fatalf("Report results expected to be in topological order but got %v", report.Results)
+	}
+	if report.Result("c") != nil {
+		t.Fatalf("Vertex c is not in the graph, Result expected to be nil")
+	}
+
+	expected := "b: failed: boom"
+	if report.Result("b").String() != expected {
+		t.Fatalf("Result stringer expected to be %q but got %q", expected, report.Result("b").String())
+	}
+	if report.Result("b").Duration() < 0 {
+		t.Fatalf("Result duration expected to not be negative")
+	}
+}
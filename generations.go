@@ -0,0 +1,133 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag
+
+// Generations return the vertices of the graph grouped in topological
+// generations.
+//
+// The first generation holds the source vertices, and every other vertex
+// belongs to the generation right after the latest of its parents. So all
+// the parents of a vertex sit in earlier generations and the vertices of a
+// generation don't depend on each other. Each generation keeps the
+// insertion order of its vertices. It returns a *CycleError if the graph is
+// not acyclic.
+func (d *DAG) Generations() ([][]*Vertex, error) {
+	sorted, err := d.TopologicalSortStable()
+	if err != nil {
+		return nil, err
+	}
+
+	depths := make(map[*Vertex]int, len(sorted))
+	var generations [][]*Vertex
+
+	for _, vertex := range sorted {
+		depth := 0
+		for _, parent := range vertex.Parents.Values() {
+			parentDepth, found := depths[parent.(*Vertex)]
+			if found && parentDepth+1 > depth {
+				depth = parentDepth + 1
+			}
+		}
+		depths[vertex] = depth
+
+		if depth == len(generations) {
+			generations = append(generations, nil)
+		}
+		generations[depth] = append(generations[depth], vertex)
+	}
+
+	return generations, nil
+}
+
+// Depth return the number of edges in the longest path from a source vertex
+// to the given vertex, which is also the index of its generation.
+func (d *DAG) Depth(vertex *Vertex) (int, error) {
+	_, err := d.GetVertex(vertex.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	depth, ok := longestPath(vertex, func(v *Vertex) []interface{} {
+		return v.Parents.Values()
+	})
+	if !ok {
+		_, err = d.TopologicalSort()
+		return 0, err
+	}
+
+	return depth, nil
+}
+
+// Height return the number of edges in the longest path from the given
+// vertex to a sink vertex.
+func (d *DAG) Height(vertex *Vertex) (int, error) {
+	_, err := d.GetVertex(vertex.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	height, ok := longestPath(vertex, func(v *Vertex) []interface{} {
+		return v.Children.Values()
+	})
+	if !ok {
+		_, err = d.TopologicalSort()
+		return 0, err
+	}
+
+	return height, nil
+}
+
+// longestPath return the number of edges in the longest path starting at
+// vertex and following the neighbours returned by next. It returns false if
+// a cycle was found on the way.
+func longestPath(vertex *Vertex, next func(*Vertex) []interface{}) (int, bool) {
+	lengths := make(map[*Vertex]int)
+	visiting := make(map[*Vertex]bool)
+
+	var visit func(v *Vertex) bool
+	visit = func(v *Vertex) bool {
+		visiting[v] = true
+
+		length := 0
+		for _, neighbour := range next(v) {
+			neighbour := neighbour.(*Vertex)
+			if visiting[neighbour] {
+				return false
+			}
+			if _, found := lengths[neighbour]; !found {
+				if !visit(neighbour) {
+					return false
+				}
+			}
+			if lengths[neighbour]+1 > length {
+				length = lengths[neighbour] + 1
+			}
+		}
+
+		visiting[v] = false
+		lengths[v] = length
+
+		return true
+	}
+
+	if !visit(vertex) {
+		return 0, false
+	}
+
+	return lengths[vertex], true
+}
@@ -0,0 +1,111 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+func TestDAG_Generations(t *testing.T) {
+	dag1, _ := newTestDAG(t,
+		[]string{"1", "2", "3", "4", "5", "6"},
+		[][2]string{{"1", "3"}, {"2", "3"}, {"3", "4"}, {"1", "4"}, {"4", "6"}, {"2", "5"}},
+	)
+
+	generations, err := dag1.Generations()
+	if err != nil {
+		t.Fatalf("Can't get DAG generations: %s", err)
+	}
+
+	expected := [][]string{{"1", "2"}, {"3", "5"}, {"4"}, {"6"}}
+	if len(generations) != len(expected) {
+		t.Fatalf("Expected %d generations but got %d", len(expected), len(generations))
+	}
+	for i := range expected {
+		if !reflect.DeepEqual(vertexIDs(generations[i]), expected[i]) {
+			t.Fatalf("Expected generation %d to be %v but got %v", i, expected[i], vertexIDs(generations[i]))
+		}
+	}
+}
+
+func TestDAG_Generations_Empty(t *testing.T) {
+	dag1 := dag.NewDAG()
+
+	generations, err := dag1.Generations()
+	if err != nil {
+		t.Fatalf("Can't get DAG generations: %s", err)
+	}
+	if len(generations) != 0 {
+		t.Fatalf("Expected 0 generations but got %d", len(generations))
+	}
+}
+
+func TestDAG_Depth(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"1", "2", "3", "4"},
+		[][2]string{{"1", "2"}, {"2", "3"}, {"1", "3"}, {"4", "3"}},
+	)
+
+	expected := map[string]int{"1": 0, "2": 1, "3": 2, "4": 0}
+	for id, expectedDepth := range expected {
+		depth, err := dag1.Depth(vertices[id])
+		if err != nil {
+			t.Fatalf("Can't get vertex %s depth: %s", id, err)
+		}
+		if depth != expectedDepth {
+			t.Fatalf("Expected vertex %s depth to be %d but got %d", id, expectedDepth, depth)
+		}
+	}
+}
+
+func TestDAG_Height(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"1", "2", "3", "4"},
+		[][2]string{{"1", "2"}, {"2", "3"}, {"1", "3"}, {"4", "3"}},
+	)
+
+	expected := map[string]int{"1": 2, "2": 1, "3": 0, "4": 1}
+	for id, expectedHeight := range expected {
+		height, err := dag1.Height(vertices[id])
+		if err != nil {
+			t.Fatalf("Can't get vertex %s height: %s", id, err)
+		}
+		if height != expectedHeight {
+			t.Fatalf("Expected vertex %s height to be %d but got %d", id, expectedHeight, height)
+		}
+	}
+}
+
+func TestDAG_Depth_VertexNotFound(t *testing.T) {
+	dag1 := dag.NewDAG()
+
+	vertex1 := dag.NewVertex("1", nil)
+
+	_, err := dag1.Depth(vertex1)
+	if err == nil {
+		t.Fatalf("Vertex don't exist, Depth should fail but it doesn't")
+	}
+
+	_, err = dag1.Height(vertex1)
+	if err == nil {
+		t.Fatalf("Vertex don't exist, Height should fail but it doesn't")
+	}
+}
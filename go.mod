@@ -1,6 +1,9 @@
 module github.com/goombaio/dag
 
+go 1.18
+
 require (
 	github.com/goombaio/orderedmap v0.0.0-20180924084748-ba921b7e2419
 	github.com/goombaio/orderedset v0.0.0-20180924084730-d1b9fdd81eca
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,635 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+const (
+	// GraphMLAttributesKey is the vertex metadata and edge Attrs key
+	// holding the GraphML attributes of a node or an edge, as a
+	// map[string]interface{} from attribute name to value.
+	GraphMLAttributesKey = "graphml.attributes"
+
+	// GraphMLValueAttribute is the name of the node attribute holding the
+	// vertex Value.
+	GraphMLValueAttribute = "value"
+
+	// GraphMLLabelAttribute is the name of the edge attribute holding the
+	// edge Label.
+	GraphMLLabelAttribute = "label"
+
+	// GraphMLWeightAttribute is the name of the edge attribute holding the
+	// edge Weight.
+	GraphMLWeightAttribute = "weight"
+)
+
+// graphMLNamespace is the GraphML XML namespace.
+const graphMLNamespace = "http://graphml.graphdrawing.org/xmlns"
+
+// graphMLKey is a GraphML attribute declaration.
+type graphMLKey struct {
+	id       string
+	domain   string
+	name     string
+	attrType string
+	def      *string
+}
+
+// graphMLData is the value of an attribute of a GraphML node or edge.
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// graphMLNode is a GraphML node.
+type graphMLNode struct {
+	ID    string        `xml:"id,attr"`
+	Data  []graphMLData `xml:"data"`
+	Graph *struct{}     `xml:"graph"`
+}
+
+// graphMLEdge is a GraphML edge.
+type graphMLEdge struct {
+	Source   string        `xml:"source,attr"`
+	Target   string        `xml:"target,attr"`
+	Directed string        `xml:"directed,attr"`
+	Data     []graphMLData `xml:"data"`
+	Graph    *struct{}     `xml:"graph"`
+}
+
+// WriteGraphML writes the graph as a GraphML document, with a node for each
+// vertex in insertion order and an edge for each child of a vertex.
+//
+// The vertex Value is written as the GraphMLValueAttribute node attribute,
+// the edge Label and Weight, unless empty and 1, as the GraphMLLabelAttribute
+// and GraphMLWeightAttribute edge attributes, and the attributes under
+// GraphMLAttributesKey in the vertex metadata and the edge Attrs as node and
+// edge attributes. Every attribute is
+// declared with a typed key, so its values must all be nil or of the same
+// type, one of bool, int, int64, float32, float64 or string.
+func (d *DAG) WriteGraphML(w io.Writer) error {
+	vertices := d.Vertices()
+	edges := d.Edges()
+
+	nodeAttrs := make(map[*Vertex]map[string]interface{}, len(vertices))
+	for _, vertex := range vertices {
+		attrs, err := graphMLAttributes(vertex)
+		if err != nil {
+			return err
+		}
+		nodeAttrs[vertex] = attrs
+	}
+	edgeAttrs := make(map[*Edge]map[string]interface{}, len(edges))
+	for _, edge := range edges {
+		attrs, err := graphMLEdgeAttributes(edge)
+		if err != nil {
+			return err
+		}
+		edgeAttrs[edge] = attrs
+	}
+
+	// Declare a key for every attribute, node ones first, each sorted by
+	// name.
+	var nodeKeys, edgeKeys []*graphMLKey
+	declared := map[string]map[string]*graphMLKey{"node": {}, "edge": {}}
+	declare := func(domain string, name string, value interface{}) error {
+		if value == nil {
+			return nil
+		}
+		attrType, ok := graphMLType(value)
+		if !ok {
+			return fmt.Errorf("%s attribute %s has unsupported type %T", domain, name, value)
+		}
+
+		key, found := declared[domain][name]
+		if !found {
+			key = &graphMLKey{domain: domain, name: name, attrType: attrType}
+			declared[domain][name] = key
+			if domain == "node" {
+				nodeKeys = append(nodeKeys, key)
+			} else {
+				edgeKeys = append(edgeKeys, key)
+			}
+		}
+		if key.attrType != attrType {
+			return fmt.Errorf("%s attribute %s has values of type %s and %s", domain, name, key.attrType, attrType)
+		}
+
+		return nil
+	}
+	for _, vertex := range vertices {
+		for name, value := range nodeAttrs[vertex] {
+			if err := declare("node", name, value); err != nil {
+				return err
+			}
+		}
+	}
+	for _, edge := range edges {
+		for name, value := range edgeAttrs[edge] {
+			if err := declare("edge", name, value); err != nil {
+				return err
+			}
+		}
+	}
+	sort.Slice(nodeKeys, func(i, j int) bool { return nodeKeys[i].name < nodeKeys[j].name })
+	sort.Slice(edgeKeys, func(i, j int) bool { return edgeKeys[i].name < edgeKeys[j].name })
+	keys := append(nodeKeys, edgeKeys...)
+	for i, key := range keys {
+		key.id = fmt.Sprintf("d%d", i)
+	}
+
+	writeData := func(b *bufio.Writer, domain string, attrs map[string]interface{}) {
+		for _, key := range keys {
+			value := attrs[key.name]
+			if key.domain != domain || value == nil {
+				continue
+			}
+			fmt.Fprintf(b, "      <data key=\"%s\">%s</data>\n", key.id, xmlEscape(graphMLFormat(value)))
+		}
+	}
+
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintf(b, "%s", xml.Header)
+	fmt.Fprintf(b, "<graphml xmlns=\"%s\">\n", graphMLNamespace)
+	for _, key := range keys {
+		fmt.Fprintf(b, "  <key id=\"%s\" for=\"%s\" attr.name=\"%s\" attr.type=\"%s\"/>\n",
+			key.id, key.domain, xmlEscape(key.name), key.attrType)
+	}
+	fmt.Fprintf(b, "  <graph id=\"G\" edgedefault=\"directed\">\n")
+
+	for _, vertex := range vertices {
+		fmt.Fprintf(b, "    <node id=\"%s\"", xmlEscape(vertex.ID))
+		if len(nodeAttrs[vertex]) == 0 {
+			fmt.Fprintf(b, "/>\n")
+			continue
+		}
+		fmt.Fprintf(b, ">\n")
+		writeData(b, "node", nodeAttrs[vertex])
+		fmt.Fprintf(b, "    </node>\n")
+	}
+
+	for _, edge := range edges {
+		fmt.Fprintf(b, "    <edge source=\"%s\" target=\"%s\"", xmlEscape(edge.Tail.ID), xmlEscape(edge.Head.ID))
+		if len(edgeAttrs[edge]) == 0 {
+			fmt.Fprintf(b, "/>\n")
+			continue
+		}
+		fmt.Fprintf(b, ">\n")
+		writeData(b, "edge", edgeAttrs[edge])
+		fmt.Fprintf(b, "    </edge>\n")
+	}
+
+	fmt.Fprintf(b, "  </graph>\n")
+	fmt.Fprintf(b, "</graphml>\n")
+
+	return b.Flush()
+}
+
+// ReadGraphML builds a graph from a GraphML document.
+//
+// Vertices are added in the order of their nodes. The GraphMLValueAttribute
+// node attribute becomes the vertex Value, a string GraphMLLabelAttribute
+// and a numeric GraphMLWeightAttribute edge attribute become the edge Label
+// and Weight, and the other node and edge attributes are kept under
+// GraphMLAttributesKey in the vertex metadata and the edge Attrs. Values are
+// converted to the Go type matching their declared type: bool, int, int64,
+// float32, float64 or string. Graph attributes are ignored.
+//
+// The document must hold a single graph. Undirected edges, nested graphs
+// and hyperedges are not supported. Errors, including a *CycleError for an
+// edge closing a cycle, are returned as a *ParseError with the position of
+// the problem.
+func ReadGraphML(r io.Reader) (*DAG, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &graphMLParser{
+		src:     src,
+		decoder: xml.NewDecoder(bytes.NewReader(src)),
+		keys:    make(map[string]*graphMLKey),
+		dag:     NewDAG(),
+	}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+
+	return p.dag, nil
+}
+
+// graphMLParser builds a graph from a GraphML document.
+type graphMLParser struct {
+	src     []byte
+	decoder *xml.Decoder
+	keys    map[string]*graphMLKey
+	dag     *DAG
+
+	// edges are added once all the nodes are known, as GraphML allows an
+	// edge to come before its nodes.
+	edges []*graphMLEdge
+
+	// edgeOffsets holds the input offset of each edge, to report errors.
+	edgeOffsets []int64
+}
+
+// errorf return a ParseError at the given input offset.
+func (p *graphMLParser) errorf(offset int64, format string, args ...interface{}) error {
+	line, column := 1, 1
+	for _, c := range p.src[:offset] {
+		if c == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+
+	return &ParseError{Line: line, Column: column, Err: fmt.Errorf(format, args...)}
+}
+
+// parse parses the whole document.
+func (p *graphMLParser) parse() error {
+	graphs := 0
+	directed := true
+
+	for {
+		offset := p.decoder.InputOffset()
+		tok, err := p.decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return p.errorf(p.decoder.InputOffset(), "%w", err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "graphml":
+		case "key":
+			if err := p.parseKey(offset, start); err != nil {
+				return err
+			}
+		case "graph":
+			graphs++
+			if graphs > 1 {
+				return p.errorf(offset, "multiple graphs are not supported")
+			}
+			directed = attr(start, "edgedefault") != "undirected"
+		case "node":
+			if err := p.parseNode(offset, start); err != nil {
+				return err
+			}
+		case "edge":
+			var edge graphMLEdge
+			if err := p.decoder.DecodeElement(&edge, &start); err != nil {
+				return p.errorf(offset, "%w", err)
+			}
+			switch {
+			case edge.Directed == "false", edge.Directed == "" && !directed:
+				return p.errorf(offset, "undirected edges are not supported")
+			case edge.Graph != nil:
+				return p.errorf(offset, "nested graphs are not supported")
+			}
+			p.edges = append(p.edges, &edge)
+			p.edgeOffsets = append(p.edgeOffsets, offset)
+		case "hyperedge":
+			return p.errorf(offset, "hyperedges are not supported")
+		default:
+			if err := p.decoder.Skip(); err != nil {
+				return p.errorf(offset, "%w", err)
+			}
+		}
+	}
+
+	if graphs == 0 {
+		return p.errorf(int64(len(p.src)), "no graph found")
+	}
+
+	for i, edge := range p.edges {
+		if err := p.addEdge(p.edgeOffsets[i], edge); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseKey parses a key declaration.
+func (p *graphMLParser) parseKey(offset int64, start xml.StartElement) error {
+	var decl struct {
+		Default *string `xml:"default"`
+	}
+	if err := p.decoder.DecodeElement(&decl, &start); err != nil {
+		return p.errorf(offset, "%w", err)
+	}
+
+	key := &graphMLKey{
+		id:       attr(start, "id"),
+		domain:   attr(start, "for"),
+		name:     attr(start, "attr.name"),
+		attrType: attr(start, "attr.type"),
+		def:      decl.Default,
+	}
+	if key.id == "" {
+		return p.errorf(offset, "key without ID")
+	}
+	if key.domain == "" {
+		key.domain = "all"
+	}
+	if key.name == "" {
+		key.name = key.id
+	}
+	if key.attrType == "" {
+		key.attrType = "string"
+	}
+	switch key.attrType {
+	case "boolean", "int", "long", "float", "double", "string":
+	default:
+		return p.errorf(offset, "key %s has unsupported type %q", key.id, key.attrType)
+	}
+	if key.def != nil {
+		if _, err := graphMLParse(key.attrType, *key.def); err != nil {
+			return p.errorf(offset, "invalid default of key %s: %w", key.id, err)
+		}
+	}
+	p.keys[key.id] = key
+
+	return nil
+}
+
+// parseNode parses a node and adds its vertex to the graph.
+func (p *graphMLParser) parseNode(offset int64, start xml.StartElement) error {
+	var node graphMLNode
+	if err := p.decoder.DecodeElement(&node, &start); err != nil {
+		return p.errorf(offset, "%w", err)
+	}
+	if node.Graph != nil {
+		return p.errorf(offset, "nested graphs are not supported")
+	}
+	if node.ID == "" {
+		return p.errorf(offset, "node without ID")
+	}
+	if _, err := p.dag.GetVertex(node.ID); err == nil {
+		return p.errorf(offset, "duplicate node %s", node.ID)
+	}
+
+	attrs, err := p.parseData(offset, "node", node.Data)
+	if err != nil {
+		return err
+	}
+
+	vertex := NewVertex(node.ID, attrs[GraphMLValueAttribute])
+	delete(attrs, GraphMLValueAttribute)
+	if len(attrs) > 0 {
+		vertex.Metadata[GraphMLAttributesKey] = attrs
+	}
+
+	if err := p.dag.AddVertex(vertex); err != nil {
+		return p.errorf(offset, "%w", err)
+	}
+
+	return nil
+}
+
+// addEdge adds an edge to the graph.
+func (p *graphMLParser) addEdge(offset int64, edge *graphMLEdge) error {
+	tail, err := p.dag.GetVertex(edge.Source)
+	if err != nil {
+		return p.errorf(offset, "edge source %s not found", edge.Source)
+	}
+	head, err := p.dag.GetVertex(edge.Target)
+	if err != nil {
+		return p.errorf(offset, "edge target %s not found", edge.Target)
+	}
+
+	attrs, err := p.parseData(offset, "edge", edge.Data)
+	if err != nil {
+		return err
+	}
+
+	if !tail.Children.Contains(head) {
+		if err := p.dag.AddEdge(tail, head); err != nil {
+			return p.errorf(offset, "%w", err)
+		}
+	}
+	e, _ := p.dag.GetEdge(tail, head)
+
+	if label, ok := attrs[GraphMLLabelAttribute].(string); ok {
+		e.Label = label
+		delete(attrs, GraphMLLabelAttribute)
+	}
+	if weight, ok := graphMLNumber(attrs[GraphMLWeightAttribute]); ok {
+		e.Weight = weight
+		delete(attrs, GraphMLWeightAttribute)
+	}
+	if len(attrs) > 0 {
+		e.Attrs[GraphMLAttributesKey] = attrs
+	}
+
+	return nil
+}
+
+// parseData return the attributes of a node or an edge, including the
+// defaults of the keys it has no data for.
+func (p *graphMLParser) parseData(offset int64, domain string, data []graphMLData) (map[string]interface{}, error) {
+	attrs := make(map[string]interface{})
+
+	for _, key := range p.keys {
+		if key.def != nil && (key.domain == domain || key.domain == "all") {
+			attrs[key.name], _ = graphMLParse(key.attrType, *key.def)
+		}
+	}
+
+	for _, data := range data {
+		key, found := p.keys[data.Key]
+		if !found {
+			return nil, p.errorf(offset, "undeclared key %s", data.Key)
+		}
+		if key.domain != domain && key.domain != "all" {
+			return nil, p.errorf(offset, "key %s is not declared for %ss", data.Key, domain)
+		}
+
+		value, err := graphMLParse(key.attrType, data.Value)
+		if err != nil {
+			return nil, p.errorf(offset, "invalid value of key %s: %w", data.Key, err)
+		}
+		attrs[key.name] = value
+	}
+
+	return attrs, nil
+}
+
+// graphMLAttributes return the GraphML node attributes of a vertex,
+// including its Value.
+func graphMLAttributes(vertex *Vertex) (map[string]interface{}, error) {
+	attrs := make(map[string]interface{})
+
+	if metadata, found := vertex.Metadata[GraphMLAttributesKey]; found {
+		metadata, ok := metadata.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("vertex %s has attributes of type %T", vertex.ID, vertex.Metadata[GraphMLAttributesKey])
+		}
+		for name, value := range metadata {
+			attrs[name] = value
+		}
+	}
+
+	if vertex.Value != nil {
+		if _, found := attrs[GraphMLValueAttribute]; found {
+			return nil, fmt.Errorf("vertex %s has both a value and a %s attribute", vertex.ID, GraphMLValueAttribute)
+		}
+		attrs[GraphMLValueAttribute] = vertex.Value
+	}
+
+	return attrs, nil
+}
+
+// graphMLEdgeAttributes return the GraphML edge attributes of an edge,
+// including its Label and Weight.
+func graphMLEdgeAttributes(edge *Edge) (map[string]interface{}, error) {
+	attrs := make(map[string]interface{})
+
+	if data, found := edge.Attrs[GraphMLAttributesKey]; found {
+		data, ok := data.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("edge (%s,%s) has attributes of type %T", edge.Tail.ID, edge.Head.ID, edge.Attrs[GraphMLAttributesKey])
+		}
+		for name, value := range data {
+			attrs[name] = value
+		}
+	}
+
+	if edge.Label != "" {
+		if _, found := attrs[GraphMLLabelAttribute]; found {
+			return nil, fmt.Errorf("edge (%s,%s) has both a label and a %s attribute", edge.Tail.ID, edge.Head.ID, GraphMLLabelAttribute)
+		}
+		attrs[GraphMLLabelAttribute] = edge.Label
+	}
+	if edge.Weight != 1 {
+		if _, found := attrs[GraphMLWeightAttribute]; found {
+			return nil, fmt.Errorf("edge (%s,%s) has both a weight and a %s attribute", edge.Tail.ID, edge.Head.ID, GraphMLWeightAttribute)
+		}
+		attrs[GraphMLWeightAttribute] = edge.Weight
+	}
+
+	return attrs, nil
+}
+
+// graphMLNumber return a numeric attribute value as a float64.
+func graphMLNumber(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case int:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case float32:
+		return float64(value), true
+	case float64:
+		return value, true
+	}
+
+	return 0, false
+}
+
+// graphMLType return the GraphML type of a value.
+func graphMLType(value interface{}) (string, bool) {
+	switch value.(type) {
+	case bool:
+		return "boolean", true
+	case int:
+		return "int", true
+	case int64:
+		return "long", true
+	case float32:
+		return "float", true
+	case float64:
+		return "double", true
+	case string:
+		return "string", true
+	}
+
+	return "", false
+}
+
+// graphMLFormat return the GraphML representation of a value.
+func graphMLFormat(value interface{}) string {
+	switch value := value.(type) {
+	case float32:
+		return strconv.FormatFloat(float64(value), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+
+	return fmt.Sprint(value)
+}
+
+// graphMLParse return the value of the given GraphML type represented by s.
+func graphMLParse(attrType string, s string) (interface{}, error) {
+	switch attrType {
+	case "boolean":
+		return strconv.ParseBool(s)
+	case "int":
+		return strconv.Atoi(s)
+	case "long":
+		return strconv.ParseInt(s, 10, 64)
+	case "float":
+		f, err := strconv.ParseFloat(s, 32)
+		return float32(f), err
+	case "double":
+		return strconv.ParseFloat(s, 64)
+	case "string":
+		return s, nil
+	}
+
+	return nil, fmt.Errorf("unsupported attribute type %q", attrType)
+}
+
+// attr return the value of an attribute of an XML element, or an empty
+// string if it has none.
+func attr(start xml.StartElement, name string) string {
+	for _, a := range start.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+
+	return ""
+}
+
+// xmlEscape return s escaped to be used as XML text or attribute value.
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	// Writing to a bytes.Buffer never fails.
+	_ = xml.EscapeText(&buf, []byte(s))
+
+	return buf.String()
+}
@@ -0,0 +1,274 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+func TestDAG_WriteGraphML(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"b", "a", "c&d"},
+		[][2]string{{"b", "a"}, {"b", "c&d"}, {"a", "c&d"}},
+	)
+	vertices["b"].Value = "Build \"all\""
+	vertices["a"].Value = "Archive"
+	vertices["b"].Metadata[dag.GraphMLAttributesKey] = map[string]interface{}{"weight": 1.5, "retries": 3}
+	vertices["a"].Metadata[dag.GraphMLAttributesKey] = map[string]interface{}{"weight": 2.0, "enabled": true}
+	edge, _ := dag1.GetEdge(vertices["b"], vertices["a"])
+	edge.Label = "artifacts"
+	edge.Attrs[dag.GraphMLAttributesKey] = map[string]interface{}{"size": int64(1 << 40)}
+	edge, _ = dag1.GetEdge(vertices["b"], vertices["c&d"])
+	edge.Weight = 0.5
+
+	var buf bytes.Buffer
+	err := dag1.WriteGraphML(&buf)
+	if err != nil {
+		t.Fatalf("Can't write GraphML: %s", err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="node" attr.name="enabled" attr.type="boolean"/>
+  <key id="d1" for="node" attr.name="retries" attr.type="int"/>
+  <key id="d2" for="node" attr.name="value" attr.type="string"/>
+  <key id="d3" for="node" attr.name="weight" attr.type="double"/>
+  <key id="d4" for="edge" attr.name="label" attr.type="string"/>
+  <key id="d5" for="edge" attr.name="size" attr.type="long"/>
+  <key id="d6" for="edge" attr.name="weight" attr.type="double"/>
+  <graph id="G" edgedefault="directed">
+    <node id="b">
+      <data key="d1">3</data>
+      <data key="d2">Build &#34;all&#34;</data>
+      <data key="d3">1.5</data>
+    </node>
+    <node id="a">
+      <data key="d0">true</data>
+      <data key="d2">Archive</data>
+      <data key="d3">2</data>
+    </node>
+    <node id="c&amp;d"/>
+    <edge source="b" target="a">
+      <data key="d4">artifacts</data>
+      <data key="d5">1099511627776</data>
+    </edge>
+    <edge source="b" target="c&amp;d">
+      <data key="d6">0.5</data>
+    </edge>
+    <edge source="a" target="c&amp;d"/>
+  </graph>
+</graphml>
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected GraphML output to be %q but got %q", expected, buf.String())
+	}
+
+	dag2, err := dag.ReadGraphML(&buf)
+	if err != nil {
+		t.Fatalf("Can't read GraphML: %s", err)
+	}
+
+	if !reflect.DeepEqual(vertexIDs(dag2.Vertices()), vertexIDs(dag1.Vertices())) {
+		t.Fatalf("Expected vertices to round trip to %v but got %v", vertexIDs(dag1.Vertices()), vertexIDs(dag2.Vertices()))
+	}
+	if !reflect.DeepEqual(edgeIDs(dag2), edgeIDs(dag1)) {
+		t.Fatalf("Expected edges to round trip to %v but got %v", edgeIDs(dag1), edgeIDs(dag2))
+	}
+	for _, vertex := range dag1.Vertices() {
+		vertex2, _ := dag2.GetVertex(vertex.ID)
+		if vertex2.Value != vertex.Value {
+			t.Fatalf("Expected vertex %s value to round trip to %v but got %v", vertex.ID, vertex.Value, vertex2.Value)
+		}
+		if !reflect.DeepEqual(vertex2.Metadata, vertex.Metadata) {
+			t.Fatalf("Expected vertex %s metadata to round trip to %v but got %v", vertex.ID, vertex.Metadata, vertex2.Metadata)
+		}
+	}
+	for i, edge2 := range dag2.Edges() {
+		edge := dag1.Edges()[i]
+		if edge2.Label != edge.Label || edge2.Weight != edge.Weight || !reflect.DeepEqual(edge2.Attrs, edge.Attrs) {
+			t.Fatalf("Expected edge %s to round trip to %s but got %s", edge, edge, edge2)
+		}
+	}
+}
+
+func TestDAG_WriteGraphML_Errors(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		metadata map[string]interface{}
+		expected string
+	}{
+		{[]int{1}, nil, "node attribute value has unsupported type []int"},
+		{nil, map[string]interface{}{dag.GraphMLAttributesKey: "x"}, "vertex 2 has attributes of type string"},
+		{"x", map[string]interface{}{dag.GraphMLAttributesKey: map[string]interface{}{"value": "y"}}, "vertex 2 has both a value and a value attribute"},
+		{nil, map[string]interface{}{dag.GraphMLAttributesKey: map[string]interface{}{"weight": 1}}, "node attribute weight has values of type double and int"},
+	}
+
+	for _, test := range tests {
+		dag1, vertices := newTestDAG(t, []string{"1", "2"}, nil)
+		vertices["1"].Metadata[dag.GraphMLAttributesKey] = map[string]interface{}{"weight": 1.0}
+		vertices["2"].Value = test.value
+		for key, value := range test.metadata {
+			vertices["2"].Metadata[key] = value
+		}
+
+		var buf bytes.Buffer
+		err := dag1.WriteGraphML(&buf)
+		if err == nil {
+			t.Fatalf("Graph can't be written, WriteGraphML should fail but it doesn't")
+		}
+		if err.Error() != test.expected {
+			t.Fatalf("Expected error message to be %q but got %q", test.expected, err.Error())
+		}
+	}
+
+	edgeTests := []struct {
+		label    string
+		attrs    interface{}
+		expected string
+	}{
+		{"", "x", "edge (1,2) has attributes of type string"},
+		{"x", map[string]interface{}{"label": "y"}, "edge (1,2) has both a label and a label attribute"},
+		{"", map[string]interface{}{"size": []int{1}}, "edge attribute size has unsupported type []int"},
+	}
+
+	for _, test := range edgeTests {
+		dag1, _ := newTestDAG(t, []string{"1", "2"}, [][2]string{{"1", "2"}})
+		edge := dag1.Edges()[0]
+		edge.Label = test.label
+		edge.Attrs[dag.GraphMLAttributesKey] = test.attrs
+
+		var buf bytes.Buffer
+		err := dag1.WriteGraphML(&buf)
+		if err == nil {
+			t.Fatalf("Graph can't be written, WriteGraphML should fail but it doesn't")
+		}
+		if err.Error() != test.expected {
+			t.Fatalf("Expected error message to be %q but got %q", test.expected, err.Error())
+		}
+	}
+}
+
+func TestReadGraphML(t *testing.T) {
+	src := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns"
+    xmlns:y="http://www.yworks.com/xml/graphml">
+  <key id="color" for="node" attr.name="color" attr.type="string">
+    <default>yellow</default>
+  </key>
+  <key id="w" for="all" attr.name="weight" attr.type="float"/>
+  <key id="g" for="graph" attr.name="title" attr.type="string"/>
+  <key id="gfx" for="node" yfiles.type="nodegraphics"/>
+  <graph id="G" edgedefault="undirected">
+    <desc>Build graph</desc>
+    <data key="g">Build</data>
+    <edge source="a" target="b" directed="true">
+      <data key="w">0.5</data>
+    </edge>
+    <edge source="a" target="b" directed="true"/>
+    <node id="a">
+      <data key="color">green</data>
+      <data key="gfx"><y:ShapeNode/></data>
+    </node>
+    <node id="b">
+      <port name="north"/>
+    </node>
+  </graph>
+</graphml>
+`
+
+	dag1, err := dag.ReadGraphML(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Can't read GraphML: %s", err)
+	}
+
+	if !reflect.DeepEqual(vertexIDs(dag1.Vertices()), []string{"a", "b"}) {
+		t.Fatalf("Expected vertices to be %v but got %v", []string{"a", "b"}, vertexIDs(dag1.Vertices()))
+	}
+	if !reflect.DeepEqual(edgeIDs(dag1), []string{"a->b"}) {
+		t.Fatalf("Expected edges to be %v but got %v", []string{"a->b"}, edgeIDs(dag1))
+	}
+
+	a, _ := dag1.GetVertex("a")
+	expectedAttrs := map[string]interface{}{"color": "green", "gfx": ""}
+	if !reflect.DeepEqual(a.Metadata[dag.GraphMLAttributesKey], expectedAttrs) {
+		t.Fatalf("Expected a attributes to be %v but got %v", expectedAttrs, a.Metadata[dag.GraphMLAttributesKey])
+	}
+	edge := dag1.Edges()[0]
+	if edge.Weight != 0.5 || len(edge.Attrs) != 0 {
+		t.Fatalf("Expected edge weight to be 0.5 with no attributes but got %s with %v", edge, edge.Attrs)
+	}
+
+	b, _ := dag1.GetVertex("b")
+	expectedAttrs = map[string]interface{}{"color": "yellow"}
+	if !reflect.DeepEqual(b.Metadata[dag.GraphMLAttributesKey], expectedAttrs) {
+		t.Fatalf("Expected b attributes to be %v but got %v", expectedAttrs, b.Metadata[dag.GraphMLAttributesKey])
+	}
+	if b.Value != nil {
+		t.Fatalf("Expected b value to be nil but got %v", b.Value)
+	}
+}
+
+func TestReadGraphML_Errors(t *testing.T) {
+	wrap := func(graph string) string {
+		return "<graphml>\n<key id=\"n\" for=\"node\" attr.type=\"int\"/>\n<graph edgedefault=\"directed\">\n" + graph + "\n</graph>\n</graphml>\n"
+	}
+
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{"<graphml></graphml>", "1:20: no graph found"},
+		{"<graphml><graph></graph><graph></graph></graphml>", "1:25: multiple graphs are not supported"},
+		{"<graphml><graph", "1:16: XML syntax error on line 1: unexpected EOF"},
+		{"<graphml><key for=\"node\"/><graph/></graphml>", "1:10: key without ID"},
+		{"<graphml><key id=\"k\" attr.type=\"date\"/><graph/></graphml>", "1:10: key k has unsupported type \"date\""},
+		{"<graphml><key id=\"k\" attr.type=\"int\"><default>x</default></key><graph/></graphml>", "1:10: invalid default of key k: strconv.Atoi: parsing \"x\": invalid syntax"},
+		{"<graphml><graph edgedefault=\"undirected\"><edge source=\"a\" target=\"b\"/></graph></graphml>", "1:42: undirected edges are not supported"},
+		{wrap("<node id=\"a\"/><node id=\"b\"/><edge source=\"a\" target=\"b\" directed=\"false\"/>"), "4:29: undirected edges are not supported"},
+		{wrap("<node/>"), "4:1: node without ID"},
+		{wrap("<node id=\"a\"/><node id=\"a\"/>"), "4:15: duplicate node a"},
+		{wrap("<node id=\"a\"><data key=\"x\">1</data></node>"), "4:1: undeclared key x"},
+		{wrap("<node id=\"a\"><data key=\"n\">one</data></node>"), "4:1: invalid value of key n: strconv.Atoi: parsing \"one\": invalid syntax"},
+		{wrap("<node id=\"a\"/><edge source=\"a\" target=\"a\"><data key=\"n\">1</data></edge>"), "4:15: key n is not declared for edges"},
+		{wrap("<node id=\"a\"><graph/></node>"), "4:1: nested graphs are not supported"},
+		{wrap("<hyperedge/>"), "4:1: hyperedges are not supported"},
+		{wrap("<node id=\"a\"/><edge source=\"a\" target=\"b\"/>"), "4:15: edge target b not found"},
+		{wrap("<node id=\"a\"/><edge source=\"b\" target=\"a\"/>"), "4:15: edge source b not found"},
+		{wrap("<node id=\"a\"/><node id=\"b\"/>\n<edge source=\"a\" target=\"b\"/>\n<edge source=\"b\" target=\"a\"/>"), "6:1: cycle detected: b -> a -> b"},
+	}
+
+	for _, test := range tests {
+		_, err := dag.ReadGraphML(strings.NewReader(test.src))
+		if err == nil {
+			t.Fatalf("GraphML %q is invalid, ReadGraphML should fail but it doesn't", test.src)
+		}
+
+		var parseErr *dag.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("Expected error for %q to be a *dag.ParseError but got %T", test.src, err)
+		}
+		if err.Error() != test.expected {
+			t.Fatalf("Expected error message for %q to be %q but got %q", test.src, test.expected, err.Error())
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EdgeProblem is the kind of inconsistency found on an edge by Integrity.
+type EdgeProblem int
+
+const (
+	// DanglingTail means the edge tail vertex doesn't belong to the graph.
+	DanglingTail EdgeProblem = iota
+
+	// DanglingHead means the edge head vertex doesn't belong to the graph.
+	DanglingHead
+
+	// MissingParent means the head is a child of the tail, but the tail is
+	// not a parent of the head.
+	MissingParent
+
+	// MissingChild means the tail is a parent of the head, but the head is
+	// not a child of the tail.
+	MissingChild
+)
+
+// String implements stringer interface.
+func (p EdgeProblem) String() string {
+	switch p {
+	case DanglingTail:
+		return "tail vertex not in the graph"
+	case DanglingHead:
+		return "head vertex not in the graph"
+	case MissingParent:
+		return "missing parent link"
+	case MissingChild:
+		return "missing child link"
+	}
+
+	return fmt.Sprintf("EdgeProblem(%d)", int(p))
+}
+
+// EdgeIssue describes an inconsistent edge found by Integrity.
+type EdgeIssue struct {
+	Tail    *Vertex
+	Head    *Vertex
+	Problem EdgeProblem
+}
+
+// String implements stringer interface.
+func (i *EdgeIssue) String() string {
+	return fmt.Sprintf("edge (%v,%v): %s", i.Tail.ID, i.Head.ID, i.Problem)
+}
+
+// IntegrityError is returned by Integrity when the graph adjacency is not
+// consistent.
+type IntegrityError struct {
+	Issues []*EdgeIssue
+}
+
+// Error implements the error interface.
+func (e *IntegrityError) Error() string {
+	issues := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		issues[i] = issue.String()
+	}
+
+	return "graph integrity check failed: " + strings.Join(issues, "; ")
+}
+
+// Integrity checks the Parents and Children of every vertex in the graph
+// agree with each other and only reference vertices in the graph.
+//
+// It returns nil for a consistent graph, or an *IntegrityError listing every
+// dangling or one-sided edge otherwise. Graphs only built and modified
+// through the DAG methods are always consistent.
+func (d *DAG) Integrity() error {
+	var issues []*EdgeIssue
+
+	for _, vertex := range d.Vertices() {
+		for _, child := range vertex.Children.Values() {
+			child := child.(*Vertex)
+			switch {
+			case !d.containsVertex(child):
+				issues = append(issues, &EdgeIssue{vertex, child, DanglingHead})
+			case !child.Parents.Contains(vertex):
+				issues = append(issues, &EdgeIssue{vertex, child, MissingParent})
+			}
+		}
+
+		for _, parent := range vertex.Parents.Values() {
+			parent := parent.(*Vertex)
+			switch {
+			case !d.containsVertex(parent):
+				issues = append(issues, &EdgeIssue{parent, vertex, DanglingTail})
+			case !parent.Children.Contains(vertex):
+				issues = append(issues, &EdgeIssue{parent, vertex, MissingChild})
+			}
+		}
+	}
+
+	if len(issues) > 0 {
+		return &IntegrityError{Issues: issues}
+	}
+
+	return nil
+}
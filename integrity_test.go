@@ -0,0 +1,82 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag_test
+
+import (
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+func TestDAG_Integrity(t *testing.T) {
+	dag1, _ := newTestDAG(t,
+		[]string{"1", "2", "3"},
+		[][2]string{{"1", "2"}, {"2", "3"}},
+	)
+
+	err := dag1.Integrity()
+	if err != nil {
+		t.Fatalf("DAG expected to be consistent: %s", err)
+	}
+}
+
+func TestDAG_Integrity_Issues(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"1", "2", "3"},
+		nil,
+	)
+	outsider := dag.NewVertex("4", nil)
+
+	// Bypass AddEdge to corrupt the graph.
+	vertices["1"].Children.Add(vertices["2"])
+	vertices["3"].Parents.Add(vertices["2"])
+	vertices["3"].Children.Add(outsider)
+	vertices["1"].Parents.Add(outsider)
+
+	err := dag1.Integrity()
+	integrityErr, ok := err.(*dag.IntegrityError)
+	if !ok {
+		t.Fatalf("Expected error to be a *dag.IntegrityError but got %v", err)
+	}
+
+	expected := []struct {
+		tail    string
+		head    string
+		problem dag.EdgeProblem
+	}{
+		{"1", "2", dag.MissingParent},
+		{"4", "1", dag.DanglingTail},
+		{"3", "4", dag.DanglingHead},
+		{"2", "3", dag.MissingChild},
+	}
+	if len(integrityErr.Issues) != len(expected) {
+		t.Fatalf("Expected %d issues but got %d: %s", len(expected), len(integrityErr.Issues), err)
+	}
+	for i, issue := range integrityErr.Issues {
+		if issue.Tail.ID != expected[i].tail || issue.Head.ID != expected[i].head || issue.Problem != expected[i].problem {
+			t.Fatalf("Expected issue %d to be (%s,%s) %s but got %s", i, expected[i].tail, expected[i].head, expected[i].problem, issue)
+		}
+	}
+
+	expectedMsg := "graph integrity check failed: edge (1,2): missing parent link; " +
+		"edge (4,1): tail vertex not in the graph; edge (3,4): head vertex not in the graph; " +
+		"edge (2,3): missing child link"
+	if err.Error() != expectedMsg {
+		t.Fatalf("Expected error message to be %q but got %q", expectedMsg, err.Error())
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/goombaio/orderedmap"
+)
+
+// JSONSchemaVersion is the version of the JSON schema written by MarshalJSON
+// and WriteJSON.
+//
+// A graph is a JSON object holding the schema version, its vertices in
+// insertion order, each with its ID, Value and Labels if any, and its edges,
+// by vertex and then child insertion order, each with its label, weight and
+// attributes if they are not the defaults of AddEdge:
+//
+//	{
+//	  "version": 1,
+//	  "vertices": [
+//	    {"id": "1", "value": "one"},
+//	    {"id": "2", "value": null, "labels": {"team": "data"}},
+//	    {"id": "3", "value": null}
+//	  ],
+//	  "edges": [
+//	    {"tail": "1", "head": "2"},
+//	    {"tail": "1", "head": "3", "label": "joins", "weight": 2, "attrs": {"key": "id"}}
+//	  ]
+//	}
+//
+// Vertex metadata is not part of the schema.
+const JSONSchemaVersion = 1
+
+// ValueDecoder decodes the JSON value of a vertex into its Value, so it can
+// have a concrete type instead of the generic ones used by encoding/json.
+type ValueDecoder func(id string, value json.RawMessage) (interface{}, error)
+
+// jsonGraph is the JSON representation of a graph.
+type jsonGraph struct {
+	Version  int          `json:"version"`
+	Vertices []jsonVertex `json:"vertices"`
+	Edges    []jsonEdge   `json:"edges"`
+}
+
+// jsonVertex is the JSON representation of a vertex.
+type jsonVertex struct {
+	ID     string            `json:"id"`
+	Value  json.RawMessage   `json:"value"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// jsonEdge is the JSON representation of an edge.
+type jsonEdge struct {
+	Tail   string                 `json:"tail"`
+	Head   string                 `json:"head"`
+	Label  string                 `json:"label,omitempty"`
+	Weight *float64               `json:"weight,omitempty"`
+	Attrs  map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface, following the schema
+// described in JSONSchemaVersion.
+func (d *DAG) MarshalJSON() ([]byte, error) {
+	graph := jsonGraph{
+		Version:  JSONSchemaVersion,
+		Vertices: []jsonVertex{},
+		Edges:    []jsonEdge{},
+	}
+
+	for _, vertex := range d.Vertices() {
+		value, err := json.Marshal(vertex.Value)
+		if err != nil {
+			return nil, fmt.Errorf("can't marshal vertex %s value: %w", vertex.ID, err)
+		}
+		graph.Vertices = append(graph.Vertices, jsonVertex{ID: vertex.ID, Value: value, Labels: vertex.Labels})
+	}
+
+	for _, edge := range d.Edges() {
+		e := jsonEdge{
+			Tail:  edge.Tail.ID,
+			Head:  edge.Head.ID,
+			Label: edge.Label,
+			Attrs: edge.Attrs,
+		}
+		if edge.Weight != 1 {
+			weight := edge.Weight
+			e.Weight = &weight
+		}
+		graph.Edges = append(graph.Edges, e)
+	}
+
+	return json.Marshal(&graph)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, following the
+// schema described in JSONSchemaVersion. Any vertex already in the graph is
+// deleted first. It can decode into a zero DAG, such as a *DAG field
+// allocated by encoding/json.
+//
+// Vertex values are decoded as by encoding/json into an interface{}. Use
+// ReadJSON to decode them into concrete types.
+func (d *DAG) UnmarshalJSON(data []byte) error {
+	decoded, err := ReadJSON(bytes.NewReader(data), nil)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// A zero DAG, such as one allocated by encoding/json, has no vertices
+	// map yet.
+	if d.edges == nil {
+		d.vertices = *orderedmap.NewOrderedMap()
+	}
+
+	for _, id := range append([]interface{}{}, d.vertices.Keys()...) {
+		d.vertices.Remove(id)
+	}
+	for _, vertex := range decoded.Vertices() {
+		d.vertices.Put(vertex.ID, vertex)
+	}
+	d.edges = decoded.edges
+	d.labels = decoded.labels
+	d.reach = nil
+
+	return nil
+}
+
+// WriteJSON writes the graph to w following the schema described in
+// JSONSchemaVersion.
+func (d *DAG) WriteJSON(w io.Writer) error {
+	data, err := d.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(data, '\n'))
+
+	return err
+}
+
+// ReadJSON builds a graph from its JSON representation, following the schema
+// described in JSONSchemaVersion.
+//
+// If decode is not nil, it is called to decode the value of every vertex.
+// Otherwise values are decoded as by encoding/json into an interface{}.
+func ReadJSON(r io.Reader, decode ValueDecoder) (*DAG, error) {
+	var graph jsonGraph
+	if err := json.NewDecoder(r).Decode(&graph); err != nil {
+		return nil, err
+	}
+	if graph.Version != JSONSchemaVersion {
+		return nil, fmt.Errorf("unsupported JSON schema version %d", graph.Version)
+	}
+
+	if decode == nil {
+		decode = func(id string, value json.RawMessage) (interface{}, error) {
+			var v interface{}
+			err := json.Unmarshal(value, &v)
+			return v, err
+		}
+	}
+
+	d := NewDAG()
+
+	for _, v := range graph.Vertices {
+		if _, err := d.GetVertex(v.ID); err == nil {
+			return nil, fmt.Errorf("duplicate vertex %s", v.ID)
+		}
+
+		raw := v.Value
+		if len(raw) == 0 {
+			raw = json.RawMessage("null")
+		}
+		value, err := decode(v.ID, raw)
+		if err != nil {
+			return nil, fmt.Errorf("can't decode vertex %s value: %w", v.ID, err)
+		}
+
+		vertex := NewVertex(v.ID, value)
+		for key, label := range v.Labels {
+			vertex.Labels[key] = label
+		}
+
+		err = d.AddVertex(vertex)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, e := range graph.Edges {
+		tail, err := d.GetVertex(e.Tail)
+		if err != nil {
+			return nil, fmt.Errorf("edge %d: %w", i, err)
+		}
+		head, err := d.GetVertex(e.Head)
+		if err != nil {
+			return nil, fmt.Errorf("edge %d: %w", i, err)
+		}
+
+		weight := 1.0
+		if e.Weight != nil {
+			weight = *e.Weight
+		}
+
+		if err := d.AddEdgeWithAttrs(tail, head, e.Label, weight, e.Attrs); err != nil {
+			return nil, fmt.Errorf("edge %d: %w", i, err)
+		}
+	}
+
+	return d, nil
+}
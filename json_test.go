@@ -0,0 +1,258 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+func TestDAG_MarshalJSON(t *testing.T) {
+	dag1 := dag.NewDAG()
+
+	vertex2 := dag.NewVertex("2", map[string]interface{}{"retries": 3})
+	vertex1 := dag.NewVertex("1", "one")
+	vertex3 := dag.NewVertex("3", nil)
+	vertex3.Labels["team"] = "data"
+
+	for _, vertex := range []*dag.Vertex{vertex2, vertex1, vertex3} {
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+	}
+	err := dag1.AddEdge(vertex2, vertex3)
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+	err = dag1.AddEdgeWithAttrs(vertex1, vertex2, "configures", 2, map[string]interface{}{"key": "id"})
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+
+	data, err := json.Marshal(dag1)
+	if err != nil {
+		t.Fatalf("Can't marshal DAG: %s", err)
+	}
+
+	expected := `{"version":1,` +
+		`"vertices":[{"id":"2","value":{"retries":3}},{"id":"1","value":"one"},{"id":"3","value":null,"labels":{"team":"data"}}],` +
+		`"edges":[{"tail":"2","head":"3"},{"tail":"1","head":"2","label":"configures","weight":2,"attrs":{"key":"id"}}]}`
+	if string(data) != expected {
+		t.Fatalf("Expected JSON to be %s but got %s", expected, data)
+	}
+
+	dag2 := dag.NewDAG()
+	err = json.Unmarshal(data, dag2)
+	if err != nil {
+		t.Fatalf("Can't unmarshal DAG: %s", err)
+	}
+
+	data2, err := json.Marshal(dag2)
+	if err != nil {
+		t.Fatalf("Can't marshal DAG: %s", err)
+	}
+	if !bytes.Equal(data, data2) {
+		t.Fatalf("Expected JSON to round trip to %s but got %s", data, data2)
+	}
+
+	vertices, err := dag2.VerticesByLabel("team=data")
+	if err != nil {
+		t.Fatalf("Can't select vertices: %s", err)
+	}
+	if !reflect.DeepEqual(vertexIDs(vertices), []string{"3"}) {
+		t.Fatalf("Expected vertices selected to be %v but got %v", []string{"3"}, vertexIDs(vertices))
+	}
+}
+
+func TestDAG_MarshalJSON_Empty(t *testing.T) {
+	data, err := json.Marshal(dag.NewDAG())
+	if err != nil {
+		t.Fatalf("Can't marshal DAG: %s", err)
+	}
+
+	expected := `{"version":1,"vertices":[],"edges":[]}`
+	if string(data) != expected {
+		t.Fatalf("Expected JSON to be %s but got %s", expected, data)
+	}
+}
+
+func TestDAG_MarshalJSON_FailsValue(t *testing.T) {
+	dag1 := dag.NewDAG()
+
+	err := dag1.AddVertex(dag.NewVertex("1", func() {}))
+	if err != nil {
+		t.Fatalf("Can't add vertex to DAG: %s", err)
+	}
+
+	_, err = json.Marshal(dag1)
+	if err == nil {
+		t.Fatalf("Value can't be marshalled, MarshalJSON should fail but it doesn't")
+	}
+}
+
+func TestDAG_UnmarshalJSON_ReplacesVertices(t *testing.T) {
+	dag1, _ := newTestDAG(t,
+		[]string{"old"},
+		nil,
+	)
+
+	err := json.Unmarshal([]byte(`{"version":1,"vertices":[{"id":"new"}],"edges":[]}`), dag1)
+	if err != nil {
+		t.Fatalf("Can't unmarshal DAG: %s", err)
+	}
+
+	if !reflect.DeepEqual(vertexIDs(dag1.Vertices()), []string{"new"}) {
+		t.Fatalf("Expected vertices to be %v but got %v", []string{"new"}, vertexIDs(dag1.Vertices()))
+	}
+}
+
+func TestDAG_UnmarshalJSON_ZeroDAG(t *testing.T) {
+	data := []byte(`{"version":1,"vertices":[{"id":"1"},{"id":"2","labels":{"env":"prod"}}],"edges":[{"tail":"1","head":"2"}]}`)
+
+	var dag1 dag.DAG
+	err := json.Unmarshal(data, &dag1)
+	if err != nil {
+		t.Fatalf("Can't unmarshal DAG: %s", err)
+	}
+
+	var pipeline struct {
+		Graph *dag.DAG
+	}
+	err = json.Unmarshal([]byte(`{"Graph":`+string(data)+`}`), &pipeline)
+	if err != nil {
+		t.Fatalf("Can't unmarshal DAG: %s", err)
+	}
+
+	for _, d := range []*dag.DAG{&dag1, pipeline.Graph} {
+		if !reflect.DeepEqual(vertexIDs(d.Vertices()), []string{"1", "2"}) {
+			t.Fatalf("Expected vertices to be %v but got %v", []string{"1", "2"}, vertexIDs(d.Vertices()))
+		}
+		if !reflect.DeepEqual(edgeIDs(d), []string{"1->2"}) {
+			t.Fatalf("Expected edges to be %v but got %v", []string{"1->2"}, edgeIDs(d))
+		}
+
+		// The graph is fully usable afterwards.
+		vertex3 := dag.NewVertex("3", nil)
+		err := d.AddVertex(vertex3)
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+		vertex2, _ := d.GetVertex("2")
+		err = d.AddEdge(vertex2, vertex3)
+		if err != nil {
+			t.Fatalf("Can't add edge to DAG: %s", err)
+		}
+		vertices, err := d.VerticesByLabel("env=prod")
+		if err != nil {
+			t.Fatalf("Can't select vertices: %s", err)
+		}
+		if !reflect.DeepEqual(vertexIDs(vertices), []string{"2"}) {
+			t.Fatalf("Expected vertices selected to be %v but got %v", []string{"2"}, vertexIDs(vertices))
+		}
+	}
+}
+
+func TestReadJSON_ValueDecoder(t *testing.T) {
+	type job struct {
+		Command string `json:"command"`
+	}
+
+	src := `{"version":1,"vertices":[
+		{"id":"build","value":{"command":"make"}},
+		{"id":"test","value":{"command":"make test"}}
+	],"edges":[{"tail":"build","head":"test"}]}`
+
+	decode := func(id string, value json.RawMessage) (interface{}, error) {
+		var j job
+		err := json.Unmarshal(value, &j)
+		return &j, err
+	}
+
+	dag1, err := dag.ReadJSON(strings.NewReader(src), decode)
+	if err != nil {
+		t.Fatalf("Can't read JSON: %s", err)
+	}
+
+	test, _ := dag1.GetVertex("test")
+	j, ok := test.Value.(*job)
+	if !ok || j.Command != "make test" {
+		t.Fatalf("Expected test value to be a *job running %q but got %#v", "make test", test.Value)
+	}
+	if dag1.Size() != 1 {
+		t.Fatalf("Dag expected to have 1 edge but got %d", dag1.Size())
+	}
+
+	var buf bytes.Buffer
+	err = dag1.WriteJSON(&buf)
+	if err != nil {
+		t.Fatalf("Can't write JSON: %s", err)
+	}
+	expected := `{"version":1,` +
+		`"vertices":[{"id":"build","value":{"command":"make"}},{"id":"test","value":{"command":"make test"}}],` +
+		`"edges":[{"tail":"build","head":"test"}]}` + "\n"
+	if buf.String() != expected {
+		t.Fatalf("Expected JSON to be %q but got %q", expected, buf.String())
+	}
+}
+
+func TestReadJSON_Errors(t *testing.T) {
+	errDecode := errors.New("bad value")
+
+	tests := []struct {
+		src      string
+		decode   dag.ValueDecoder
+		expected string
+	}{
+		{`{"version":2}`, nil, "unsupported JSON schema version 2"},
+		{`{"version":1,"vertices":[{"id":"1"},{"id":"1"}]}`, nil, "duplicate vertex 1"},
+		{`{"version":1,"vertices":[{"id":"1"}],"edges":[{"tail":"1","head":"2"}]}`, nil, "edge 0: vertex 2 not found in the graph"},
+		{
+			`{"version":1,"vertices":[{"id":"1"},{"id":"2"}],"edges":[{"tail":"1","head":"2"},{"tail":"2","head":"1"}]}`,
+			nil,
+			"edge 1: cycle detected: 2 -> 1 -> 2",
+		},
+		{
+			`{"version":1,"vertices":[{"id":"1","value":3}]}`,
+			func(id string, value json.RawMessage) (interface{}, error) { return nil, errDecode },
+			"can't decode vertex 1 value: bad value",
+		},
+		{`{"version":1,"vertices":[{"id":"1","labels":{"env":"prod dev"}}]}`, nil, "vertex 1: invalid label value \"prod dev\""},
+	}
+
+	for _, test := range tests {
+		_, err := dag.ReadJSON(strings.NewReader(test.src), test.decode)
+		if err == nil {
+			t.Fatalf("JSON %s is invalid, ReadJSON should fail but it doesn't", test.src)
+		}
+		if err.Error() != test.expected {
+			t.Fatalf("Expected error message for %s to be %q but got %q", test.src, test.expected, err.Error())
+		}
+	}
+
+	_, err := dag.ReadJSON(strings.NewReader(`{"version":1,"vertices":[{"id":"1"},{"id":"2"}],"edges":[{"tail":"1","head":"2"},{"tail":"2","head":"1"}]}`), nil)
+	var cycleErr *dag.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected error to wrap a *dag.CycleError but got %v", err)
+	}
+}
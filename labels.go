@@ -0,0 +1,523 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// selectorOperator is the operator of a label requirement.
+type selectorOperator int
+
+const (
+	selectorEquals selectorOperator = iota
+	selectorNotEquals
+	selectorIn
+	selectorNotIn
+	selectorExists
+	selectorDoesNotExist
+)
+
+// labelRequirement is one of the requirements of a Selector, such as
+// env=prod or tier in (web,api).
+type labelRequirement struct {
+	key      string
+	operator selectorOperator
+	values   []string
+}
+
+// Selector type implements a label selector, which matches the vertices
+// whose labels meet all its requirements.
+type Selector struct {
+	requirements []labelRequirement
+}
+
+// ParseSelector parses a label selector, following the syntax of Kubernetes
+// label selectors: a comma separated list of requirements, all of which
+// must be met, each one of:
+//
+//	key=value, key==value  the label is set to value
+//	key!=value             the label is not set to value, or not set
+//	key in (v1,v2)         the label is set to one of the values
+//	key notin (v1,v2)      the label is not set to any of the values, or not set
+//	key                    the label is set
+//	!key                   the label is not set
+//
+// Keys and values are made of letters, digits and the characters "-", "_",
+// ".", and "/". The values of a set can't be empty. An empty selector
+// matches every vertex.
+func ParseSelector(selector string) (*Selector, error) {
+	p := &selectorParser{src: selector}
+
+	s := &Selector{}
+
+	p.skipSpaces()
+	for p.pos < len(p.src) {
+		requirement, err := p.parseRequirement()
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %w", selector, err)
+		}
+		s.requirements = append(s.requirements, requirement)
+
+		p.skipSpaces()
+		if p.pos == len(p.src) {
+			break
+		}
+		if p.src[p.pos] != ',' {
+			return nil, fmt.Errorf("invalid selector %q: expected \",\" at offset %d", selector, p.pos)
+		}
+		p.pos++
+		p.skipSpaces()
+		if p.pos == len(p.src) {
+			return nil, fmt.Errorf("invalid selector %q: expected requirement at offset %d", selector, p.pos)
+		}
+	}
+
+	return s, nil
+}
+
+// Matches return if the given labels meet all the requirements of the
+// selector.
+func (s *Selector) Matches(labels map[string]string) bool {
+	for _, requirement := range s.requirements {
+		if !requirement.matches(labels) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String implements stringer interface and prints the selector in its
+// canonical form.
+func (s *Selector) String() string {
+	requirements := make([]string, len(s.requirements))
+	for i, requirement := range s.requirements {
+		requirements[i] = requirement.String()
+	}
+
+	return strings.Join(requirements, ",")
+}
+
+// matches return if the given labels meet the requirement.
+func (r *labelRequirement) matches(labels map[string]string) bool {
+	value, found := labels[r.key]
+
+	switch r.operator {
+	case selectorEquals, selectorIn:
+		return found && r.hasValue(value)
+	case selectorNotEquals, selectorNotIn:
+		return !found || !r.hasValue(value)
+	case selectorExists:
+		return found
+	default:
+		return !found
+	}
+}
+
+// hasValue return if value is one of the values of the requirement.
+func (r *labelRequirement) hasValue(value string) bool {
+	for _, v := range r.values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String implements stringer interface.
+func (r *labelRequirement) String() string {
+	switch r.operator {
+	case selectorEquals:
+		return r.key + "=" + r.values[0]
+	case selectorNotEquals:
+		return r.key + "!=" + r.values[0]
+	case selectorIn:
+		return r.key + " in (" + strings.Join(r.values, ",") + ")"
+	case selectorNotIn:
+		return r.key + " notin (" + strings.Join(r.values, ",") + ")"
+	case selectorExists:
+		return r.key
+	default:
+		return "!" + r.key
+	}
+}
+
+// selectorParser parses a label selector.
+type selectorParser struct {
+	src string
+	pos int
+}
+
+// parseRequirement parses a single requirement of a selector.
+func (p *selectorParser) parseRequirement() (labelRequirement, error) {
+	requirement := labelRequirement{}
+
+	if p.src[p.pos] == '!' {
+		p.pos++
+		p.skipSpaces()
+		requirement.operator = selectorDoesNotExist
+		requirement.key = p.word()
+		if requirement.key == "" {
+			return requirement, fmt.Errorf("expected label key at offset %d", p.pos)
+		}
+		return requirement, nil
+	}
+
+	requirement.key = p.word()
+	if requirement.key == "" {
+		return requirement, fmt.Errorf("expected label key at offset %d", p.pos)
+	}
+	p.skipSpaces()
+
+	switch {
+	case p.pos == len(p.src), p.src[p.pos] == ',':
+		requirement.operator = selectorExists
+		return requirement, nil
+	case strings.HasPrefix(p.src[p.pos:], "=="):
+		p.pos += 2
+		requirement.operator = selectorEquals
+	case strings.HasPrefix(p.src[p.pos:], "!="):
+		p.pos += 2
+		requirement.operator = selectorNotEquals
+	case p.src[p.pos] == '=':
+		p.pos++
+		requirement.operator = selectorEquals
+	default:
+		offset := p.pos
+		switch operator := p.word(); operator {
+		case "in":
+			requirement.operator = selectorIn
+		case "notin":
+			requirement.operator = selectorNotIn
+		case "":
+			return requirement, fmt.Errorf("unexpected character %q at offset %d", p.src[offset], offset)
+		default:
+			return requirement, fmt.Errorf("unknown operator %q at offset %d", operator, offset)
+		}
+
+		values, err := p.parseSet()
+		if err != nil {
+			return requirement, err
+		}
+		requirement.values = values
+		return requirement, nil
+	}
+
+	p.skipSpaces()
+	requirement.values = []string{p.word()}
+
+	return requirement, nil
+}
+
+// parseSet parses a set of values: '(' value (',' value)* ')'
+func (p *selectorParser) parseSet() ([]string, error) {
+	p.skipSpaces()
+	if p.pos == len(p.src) || p.src[p.pos] != '(' {
+		return nil, fmt.Errorf("expected \"(\" at offset %d", p.pos)
+	}
+	p.pos++
+
+	var values []string
+	for {
+		p.skipSpaces()
+		value := p.word()
+		if value == "" {
+			return nil, fmt.Errorf("expected label value at offset %d", p.pos)
+		}
+		p.skipSpaces()
+
+		found := false
+		for _, v := range values {
+			found = found || v == value
+		}
+		if !found {
+			values = append(values, value)
+		}
+
+		if p.pos == len(p.src) {
+			return nil, fmt.Errorf("expected \")\" at offset %d", p.pos)
+		}
+		switch p.src[p.pos] {
+		case ')':
+			p.pos++
+			return values, nil
+		case ',':
+			p.pos++
+		default:
+			return nil, fmt.Errorf("unexpected character %q at offset %d", p.src[p.pos], p.pos)
+		}
+	}
+}
+
+// word reads a label key or value, which may be empty.
+func (p *selectorParser) word() string {
+	start := p.pos
+	for p.pos < len(p.src) && isLabelChar(p.src[p.pos]) {
+		p.pos++
+	}
+
+	return p.src[start:p.pos]
+}
+
+// skipSpaces skips any white space.
+func (p *selectorParser) skipSpaces() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// isLabelChar return if c can be part of a label key or value.
+func isLabelChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	case c == '-', c == '_', c == '.', c == '/':
+		return true
+	}
+
+	return false
+}
+
+// isLabel return if s is a valid label key or value. Keys can't be empty.
+func isLabel(s string, key bool) bool {
+	if key && s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isLabelChar(s[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkLabels checks the keys and values of a set of labels, in key order.
+func checkLabels(labels map[string]string) error {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !isLabel(key, true) {
+			return fmt.Errorf("invalid label key %q", key)
+		}
+		if !isLabel(labels[key], false) {
+			return fmt.Errorf("invalid label value %q", labels[key])
+		}
+	}
+
+	return nil
+}
+
+// VerticesByLabel return the vertices of the graph whose labels match a
+// selector, as described in ParseSelector, in insertion order.
+//
+// Vertices are looked up in an index kept up to date by AddVertex,
+// DeleteVertex, SetVertexLabel and DeleteVertexLabel, so only the ones with
+// the label values required by the selector are checked.
+func (d *DAG) VerticesByLabel(selector string) ([]*Vertex, error) {
+	s, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.labels.query(s), nil
+}
+
+// SetVertexLabel sets a label of a vertex of the graph. Keys and values are
+// made of letters, digits and the characters "-", "_", "." and "/", and
+// keys can't be empty.
+func (d *DAG) SetVertexLabel(vertex *Vertex, key string, value string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.containsVertex(vertex) {
+		return fmt.Errorf("vertex %s not found in the graph", vertex.ID)
+	}
+	if !isLabel(key, true) {
+		return fmt.Errorf("invalid label key %q", key)
+	}
+	if !isLabel(value, false) {
+		return fmt.Errorf("invalid label value %q", value)
+	}
+
+	if vertex.Labels == nil {
+		vertex.Labels = make(map[string]string)
+	}
+	vertex.Labels[key] = value
+	d.labels.replace(vertex, vertex)
+
+	return nil
+}
+
+// DeleteVertexLabel deletes a label of a vertex of the graph, if set.
+func (d *DAG) DeleteVertexLabel(vertex *Vertex, key string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.containsVertex(vertex) {
+		return fmt.Errorf("vertex %s not found in the graph", vertex.ID)
+	}
+
+	delete(vertex.Labels, key)
+	d.labels.replace(vertex, vertex)
+
+	return nil
+}
+
+// labelIndex is an inverted index of the labels of the vertices of a graph.
+type labelIndex struct {
+	// vertices holds the vertices with each value of each label key.
+	vertices map[string]map[string]map[*Vertex]bool
+
+	// labels holds the labels each vertex was indexed with, so the index
+	// stays consistent even if they are changed directly afterwards.
+	labels map[*Vertex]map[string]string
+
+	// rank holds the position of each vertex in insertion order, to sort
+	// the results.
+	rank     map[*Vertex]int
+	nextRank int
+}
+
+// newLabelIndex creates a new empty label index.
+func newLabelIndex() *labelIndex {
+	ix := &labelIndex{
+		vertices: make(map[string]map[string]map[*Vertex]bool),
+		labels:   make(map[*Vertex]map[string]string),
+		rank:     make(map[*Vertex]int),
+	}
+
+	return ix
+}
+
+// add indexes a vertex added to the graph.
+func (ix *labelIndex) add(vertex *Vertex) {
+	ix.insert(vertex, ix.nextRank)
+	ix.nextRank++
+}
+
+// replace indexes a vertex taking the place of another one, or the same one
+// again, keeping its position.
+func (ix *labelIndex) replace(old *Vertex, vertex *Vertex) {
+	rank := ix.rank[old]
+	ix.remove(old)
+	ix.insert(vertex, rank)
+}
+
+// insert indexes a vertex at the given position.
+func (ix *labelIndex) insert(vertex *Vertex, rank int) {
+	labels := make(map[string]string, len(vertex.Labels))
+	for key, value := range vertex.Labels {
+		labels[key] = value
+
+		values, found := ix.vertices[key]
+		if !found {
+			values = make(map[string]map[*Vertex]bool)
+			ix.vertices[key] = values
+		}
+		if values[value] == nil {
+			values[value] = make(map[*Vertex]bool)
+		}
+		values[value][vertex] = true
+	}
+
+	ix.labels[vertex] = labels
+	ix.rank[vertex] = rank
+}
+
+// remove removes a vertex from the index.
+func (ix *labelIndex) remove(vertex *Vertex) {
+	for key, value := range ix.labels[vertex] {
+		delete(ix.vertices[key][value], vertex)
+		if len(ix.vertices[key][value]) == 0 {
+			delete(ix.vertices[key], value)
+		}
+		if len(ix.vertices[key]) == 0 {
+			delete(ix.vertices, key)
+		}
+	}
+
+	delete(ix.labels, vertex)
+	delete(ix.rank, vertex)
+}
+
+// query return the vertices matching a selector in insertion order.
+//
+// Only the vertices with the values required by the most selective
+// equality, set or existence requirement are checked, or all of them if
+// there is none. The sets of vertices of a requirement are disjoint, as a
+// vertex has a single value for each key.
+func (ix *labelIndex) query(s *Selector) []*Vertex {
+	var candidates []map[*Vertex]bool
+	size := -1
+	for _, requirement := range s.requirements {
+		var sets []map[*Vertex]bool
+		switch requirement.operator {
+		case selectorEquals, selectorIn:
+			for _, value := range requirement.values {
+				if set, found := ix.vertices[requirement.key][value]; found {
+					sets = append(sets, set)
+				}
+			}
+		case selectorExists:
+			for _, set := range ix.vertices[requirement.key] {
+				sets = append(sets, set)
+			}
+		default:
+			continue
+		}
+
+		n := 0
+		for _, set := range sets {
+			n += len(set)
+		}
+		if size == -1 || n < size {
+			candidates, size = sets, n
+		}
+	}
+
+	vertices := []*Vertex{}
+	check := func(vertex *Vertex) {
+		if s.Matches(ix.labels[vertex]) {
+			vertices = append(vertices, vertex)
+		}
+	}
+	if size == -1 {
+		for vertex := range ix.rank {
+			check(vertex)
+		}
+	}
+	for _, set := range candidates {
+		for vertex := range set {
+			check(vertex)
+		}
+	}
+	sort.Slice(vertices, func(i, j int) bool { return ix.rank[vertices[i]] < ix.rank[vertices[j]] })
+
+	return vertices
+}
@@ -0,0 +1,283 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		expected string
+	}{
+		{"", ""},
+		{"env=prod", "env=prod"},
+		{" env == prod , tier!=web ", "env=prod,tier!=web"},
+		{"env=", "env="},
+		{"tier in (web, api,web)", "tier in (web,api)"},
+		{"tier notin (web)", "tier notin (web)"},
+		{"team.example.com/owner,!deprecated", "team.example.com/owner,!deprecated"},
+	}
+
+	for _, test := range tests {
+		s, err := dag.ParseSelector(test.selector)
+		if err != nil {
+			t.Fatalf("Can't parse selector %q: %s", test.selector, err)
+		}
+		if s.String() != test.expected {
+			t.Fatalf("Expected selector %q to be %q but got %q", test.selector, test.expected, s.String())
+		}
+	}
+}
+
+func TestParseSelector_Errors(t *testing.T) {
+	tests := []struct {
+		selector string
+		expected string
+	}{
+		{"env=prod,", "invalid selector \"env=prod,\": expected requirement at offset 9"},
+		{",env", "invalid selector \",env\": expected label key at offset 0"},
+		{"!", "invalid selector \"!\": expected label key at offset 1"},
+		{"env prod", "invalid selector \"env prod\": unknown operator \"prod\" at offset 4"},
+		{"env > 1", "invalid selector \"env > 1\": unexpected character '>' at offset 4"},
+		{"env in web", "invalid selector \"env in web\": expected \"(\" at offset 7"},
+		{"env in (web", "invalid selector \"env in (web\": expected \")\" at offset 11"},
+		{"env in (web;api)", "invalid selector \"env in (web;api)\": unexpected character ';' at offset 11"},
+		{"env in ()", "invalid selector \"env in ()\": expected label value at offset 8"},
+		{"env in (,)", "invalid selector \"env in (,)\": expected label value at offset 8"},
+		{"env notin (web, )", "invalid selector \"env notin (web, )\": expected label value at offset 16"},
+		{"env=prod tier=web", "invalid selector \"env=prod tier=web\": expected \",\" at offset 9"},
+	}
+
+	for _, test := range tests {
+		_, err := dag.ParseSelector(test.selector)
+		if err == nil {
+			t.Fatalf("Selector %q is invalid, ParseSelector should fail but it doesn't", test.selector)
+		}
+		if err.Error() != test.expected {
+			t.Fatalf("Expected error message for %q to be %q but got %q", test.selector, test.expected, err.Error())
+		}
+	}
+}
+
+func TestSelector_Matches(t *testing.T) {
+	labels := map[string]string{"env": "prod", "tier": "web"}
+
+	tests := []struct {
+		selector string
+		expected bool
+	}{
+		{"", true},
+		{"env=prod", true},
+		{"env=dev", false},
+		{"env!=dev", true},
+		{"team!=data", true},
+		{"tier in (api,web)", true},
+		{"tier notin (api,web)", false},
+		{"team notin (data)", true},
+		{"team in (data)", false},
+		{"env", true},
+		{"team", false},
+		{"!team", true},
+		{"!env", false},
+		{"env=prod,tier=api", false},
+	}
+
+	for _, test := range tests {
+		s, err := dag.ParseSelector(test.selector)
+		if err != nil {
+			t.Fatalf("Can't parse selector %q: %s", test.selector, err)
+		}
+		if s.Matches(labels) != test.expected {
+			t.Fatalf("Expected selector %q to match %v to be %v", test.selector, labels, test.expected)
+		}
+	}
+}
+
+// labelQuery is a selector and the IDs of the vertices it selects.
+type labelQuery struct {
+	selector string
+	expected []string
+}
+
+// checkVerticesByLabel checks the vertices selected by each query.
+func checkVerticesByLabel(t *testing.T, dag1 *dag.DAG, queries []labelQuery) {
+	t.Helper()
+
+	for _, query := range queries {
+		vertices, err := dag1.VerticesByLabel(query.selector)
+		if err != nil {
+			t.Fatalf("Can't select vertices: %s", err)
+		}
+		if !reflect.DeepEqual(vertexIDs(vertices), query.expected) {
+			t.Fatalf("Expected vertices selected by %q to be %v but got %v", query.selector, query.expected, vertexIDs(vertices))
+		}
+	}
+}
+
+func TestDAG_VerticesByLabel(t *testing.T) {
+	dag1 := dag.NewDAG()
+
+	labels := map[string]map[string]string{
+		"ingest":  {"team": "data", "env": "prod", "tier": "batch"},
+		"api":     {"team": "web", "env": "prod", "tier": "api"},
+		"site":    {"team": "web", "env": "staging"},
+		"metrics": {"team": "data", "env": "staging", "tier": "batch"},
+		"legacy":  {},
+	}
+	for _, id := range []string{"ingest", "api", "site", "metrics", "legacy"} {
+		vertex := dag.NewVertex(id, nil)
+		vertex.Labels = labels[id]
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+	}
+
+	queries := []labelQuery{
+		{"", []string{"ingest", "api", "site", "metrics", "legacy"}},
+		{"team=data", []string{"ingest", "metrics"}},
+		{"team=data,env=prod", []string{"ingest"}},
+		{"env!=prod", []string{"site", "metrics", "legacy"}},
+		{"tier in (api,batch)", []string{"ingest", "api", "metrics"}},
+		{"tier notin (batch)", []string{"api", "site", "legacy"}},
+		{"tier", []string{"ingest", "api", "metrics"}},
+		{"!tier,team", []string{"site"}},
+		{"team=ops", []string{}},
+	}
+	checkVerticesByLabel(t, dag1, queries)
+
+	// Replacing a vertex keeps its position.
+	site := dag.NewVertex("site", nil)
+	site.Labels = map[string]string{"team": "web", "env": "staging"}
+	err := dag1.AddVertex(site)
+	if err != nil {
+		t.Fatalf("Can't add vertex to DAG: %s", err)
+	}
+	checkVerticesByLabel(t, dag1, queries)
+
+	// Labels changed directly are not seen until the vertex is added again.
+	legacy, _ := dag1.GetVertex("legacy")
+	legacy.Labels["team"] = "ops"
+	checkVerticesByLabel(t, dag1, queries)
+
+	err = dag1.AddVertex(legacy)
+	if err != nil {
+		t.Fatalf("Can't add vertex to DAG: %s", err)
+	}
+	checkVerticesByLabel(t, dag1, []labelQuery{
+		{"!tier,team", []string{"site", "legacy"}},
+		{"team=ops", []string{"legacy"}},
+	})
+
+	err = dag1.SetVertexLabel(site, "tier", "web")
+	if err != nil {
+		t.Fatalf("Can't set vertex label: %s", err)
+	}
+	err = dag1.DeleteVertexLabel(legacy, "team")
+	if err != nil {
+		t.Fatalf("Can't delete vertex label: %s", err)
+	}
+	ingest, _ := dag1.GetVertex("ingest")
+	err = dag1.DeleteVertex(ingest)
+	if err != nil {
+		t.Fatalf("Can't delete vertex from DAG: %s", err)
+	}
+	checkVerticesByLabel(t, dag1, []labelQuery{
+		{"tier", []string{"api", "site", "metrics"}},
+		{"!team", []string{"legacy"}},
+		{"team=data", []string{"metrics"}},
+	})
+
+	_, err = dag1.VerticesByLabel("env in")
+	if err == nil {
+		t.Fatalf("Selector is invalid, VerticesByLabel should fail but it doesn't")
+	}
+}
+
+func TestDAG_SetVertexLabel_Fails(t *testing.T) {
+	dag1, vertices := newTestDAG(t, []string{"1"}, nil)
+
+	tests := []struct {
+		vertex   *dag.Vertex
+		key      string
+		value    string
+		expected string
+	}{
+		{dag.NewVertex("1", nil), "env", "prod", "vertex 1 not found in the graph"},
+		{vertices["1"], "", "prod", "invalid label key \"\""},
+		{vertices["1"], "env name", "prod", "invalid label key \"env name\""},
+		{vertices["1"], "env", "prod,dev", "invalid label value \"prod,dev\""},
+	}
+
+	for _, test := range tests {
+		err := dag1.SetVertexLabel(test.vertex, test.key, test.value)
+		if err == nil {
+			t.Fatalf("Label can't be set, SetVertexLabel should fail but it doesn't")
+		}
+		if err.Error() != test.expected {
+			t.Fatalf("Expected error message to be %q but got %q", test.expected, err.Error())
+		}
+	}
+
+	err := dag1.DeleteVertexLabel(dag.NewVertex("1", nil), "env")
+	if err == nil {
+		t.Fatalf("Vertex doesn't belong to the graph, DeleteVertexLabel should fail but it doesn't")
+	}
+}
+
+func TestDAG_AddVertex_FailsLabels(t *testing.T) {
+	dag1 := dag.NewDAG()
+
+	tests := []struct {
+		labels   map[string]string
+		expected string
+	}{
+		{map[string]string{"": "prod"}, "vertex 1: invalid label key \"\""},
+		{map[string]string{"env": "prod", "tier name": "web"}, "vertex 1: invalid label key \"tier name\""},
+		{map[string]string{"env": "prod,dev"}, "vertex 1: invalid label value \"prod,dev\""},
+	}
+
+	for _, test := range tests {
+		vertex := dag.NewVertex("1", nil)
+		vertex.Labels = test.labels
+
+		err := dag1.AddVertex(vertex)
+		if err == nil {
+			t.Fatalf("Labels %v are invalid, AddVertex should fail but it doesn't", test.labels)
+		}
+		if err.Error() != test.expected {
+			t.Fatalf("Expected error message to be %q but got %q", test.expected, err.Error())
+		}
+	}
+
+	// Invalid vertices are not added.
+	if dag1.Order() != 0 {
+		t.Fatalf("DAG number of vertices expected to be 0 but got %d", dag1.Order())
+	}
+	vertices, err := dag1.VerticesByLabel("env")
+	if err != nil {
+		t.Fatalf("Can't get vertices by label: %s", err)
+	}
+	if len(vertices) != 0 {
+		t.Fatalf("Expected no vertices with label env but got %d", len(vertices))
+	}
+}
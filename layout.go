@@ -0,0 +1,486 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"unicode/utf8"
+)
+
+// CrossingHeuristic is the heuristic used by Layout to order the vertices of
+// each layer so that edges cross as little as possible.
+type CrossingHeuristic int
+
+const (
+	// Barycenter orders vertices by the mean position of their neighbours
+	// in the adjacent layer.
+	Barycenter CrossingHeuristic = iota
+
+	// Median orders vertices by the median position of their neighbours in
+	// the adjacent layer.
+	Median
+)
+
+// String implements stringer interface.
+func (h CrossingHeuristic) String() string {
+	switch h {
+	case Barycenter:
+		return "barycenter"
+	case Median:
+		return "median"
+	}
+
+	return fmt.Sprintf("CrossingHeuristic(%d)", int(h))
+}
+
+// LayoutOptions configures the layout computed by Layout.
+type LayoutOptions struct {
+	// Direction is the direction of the edges, "TB" for top to bottom or
+	// "LR" for left to right. It defaults to top to bottom.
+	Direction string
+
+	// Label return the label of a vertex. If nil, vertices are labelled
+	// with their ID. Use ValueLabel to label them with their Value.
+	Label func(v *Vertex) string
+
+	// Size return the width and height of a vertex. If nil, vertices are
+	// sized to fit their label.
+	Size func(v *Vertex) (width float64, height float64)
+
+	// LayerSpacing is the space between two layers. It defaults to 50.
+	LayerSpacing float64
+
+	// NodeSpacing is the space between two vertices of a layer. It defaults
+	// to 20.
+	NodeSpacing float64
+
+	// Heuristic is the crossing minimisation heuristic. It defaults to
+	// Barycenter.
+	Heuristic CrossingHeuristic
+
+	// Iterations is the number of down and up sweeps of the crossing
+	// minimisation. It defaults to 24.
+	Iterations int
+}
+
+// Layout is the position of the vertices and edges of a graph drawn as a
+// layered diagram, as computed by DAG.Layout.
+//
+// Coordinates grow rightwards and downwards, starting at zero. The diagram
+// fits in a Width by Height rectangle. Nodes are sorted by layer, and edges
+// by tail.
+type Layout struct {
+	Width  float64       `json:"width"`
+	Height float64       `json:"height"`
+	Nodes  []*LayoutNode `json:"nodes"`
+	Edges  []*LayoutEdge `json:"edges"`
+}
+
+// LayoutNode is the position of a vertex in a Layout.
+type LayoutNode struct {
+	Vertex *Vertex `json:"-"`
+	ID     string  `json:"id"`
+	Label  string  `json:"label"`
+
+	// Layer is the index of the layer of the vertex, which is its depth.
+	Layer int `json:"layer"`
+
+	// X and Y are the coordinates of the center of the vertex.
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// LayoutEdge is the route of an edge in a Layout.
+type LayoutEdge struct {
+	Tail *Vertex `json:"-"`
+	Head *Vertex `json:"-"`
+
+	// Points is the polyline followed by the edge, from the border of its
+	// tail to the border of its head, bending between layers.
+	Points []Point `json:"points"`
+}
+
+// Point is a position in a Layout.
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// layoutNode is a vertex, or a dummy node where an edge crosses a layer, in
+// the layout being computed.
+type layoutNode struct {
+	node   *LayoutNode // nil for a dummy node
+	layer  int
+	order  int
+	x      float64
+	width  float64
+	height float64
+	up     []*layoutNode
+	down   []*layoutNode
+}
+
+// Layout computes a layered layout of the graph, following the method of
+// Sugiyama et al.
+//
+// Vertices are assigned to layers by depth, so every edge goes from a layer
+// to a later one, with a dummy node in every layer it crosses. The vertices
+// of each layer are then ordered to reduce edge crossings, sweeping the
+// layers down and up with the chosen heuristic and keeping the best order
+// found. Finally every vertex is placed as close as possible to its
+// neighbours in the adjacent layers without overlapping the others.
+//
+// If opts is nil the defaults are used. It returns a *CycleError if the
+// graph is not acyclic.
+func (d *DAG) Layout(opts *LayoutOptions) (*Layout, error) {
+	if opts == nil {
+		opts = &LayoutOptions{}
+	}
+	switch opts.Direction {
+	case "", "TB", "LR":
+	default:
+		return nil, fmt.Errorf("invalid direction %q", opts.Direction)
+	}
+	label := opts.Label
+	if label == nil {
+		label = func(v *Vertex) string {
+			return v.ID
+		}
+	}
+	size := opts.Size
+	if size == nil {
+		size = func(v *Vertex) (float64, float64) {
+			return float64(7*utf8.RuneCountInString(label(v)) + 20), 30
+		}
+	}
+	layerSpacing := opts.LayerSpacing
+	if layerSpacing <= 0 {
+		layerSpacing = 50
+	}
+	nodeSpacing := opts.NodeSpacing
+	if nodeSpacing <= 0 {
+		nodeSpacing = 20
+	}
+	iterations := opts.Iterations
+	if iterations <= 0 {
+		iterations = 24
+	}
+
+	generations, err := d.Generations()
+	if err != nil {
+		return nil, err
+	}
+
+	layout := &Layout{}
+
+	// Layer assignment.
+	layers := make([][]*layoutNode, len(generations))
+	nodes := make(map[*Vertex]*layoutNode, d.Order())
+	for i, generation := range generations {
+		for _, vertex := range generation {
+			node := &LayoutNode{Vertex: vertex, ID: vertex.ID, Label: label(vertex), Layer: i}
+			node.Width, node.Height = size(vertex)
+			layout.Nodes = append(layout.Nodes, node)
+
+			n := &layoutNode{node: node, layer: i, width: node.Width, height: node.Height}
+			if opts.Direction == "LR" {
+				n.width, n.height = n.height, n.width
+			}
+			nodes[vertex] = n
+			layers[i] = append(layers[i], n)
+		}
+	}
+
+	// Dummy nodes for long edges.
+	var chains [][]*layoutNode
+	for _, generation := range generations {
+		for _, vertex := range generation {
+			for _, child := range vertex.Children.Values() {
+				tail, head := nodes[vertex], nodes[child.(*Vertex)]
+				if head == nil {
+					continue
+				}
+
+				chain := []*layoutNode{tail}
+				for layer := tail.layer + 1; layer < head.layer; layer++ {
+					dummy := &layoutNode{layer: layer}
+					layers[layer] = append(layers[layer], dummy)
+					chain = append(chain, dummy)
+				}
+				chain = append(chain, head)
+
+				for i := 1; i < len(chain); i++ {
+					chain[i-1].down = append(chain[i-1].down, chain[i])
+					chain[i].up = append(chain[i].up, chain[i-1])
+				}
+				chains = append(chains, chain)
+				layout.Edges = append(layout.Edges, &LayoutEdge{Tail: vertex, Head: child.(*Vertex)})
+			}
+		}
+	}
+
+	orderLayers(layers, opts.Heuristic, iterations)
+	placeLayers(layers, nodeSpacing, iterations)
+
+	// Vertical coordinates, and size of the layout.
+	top := 0.0
+	ys := make([]float64, len(layers))
+	for i, layer := range layers {
+		thickness := 0.0
+		for _, n := range layer {
+			thickness = math.Max(thickness, n.height)
+		}
+		if i > 0 {
+			top += layerSpacing
+		}
+		ys[i] = top + thickness/2
+		top += thickness
+	}
+	width := 0.0
+	for _, layer := range layers {
+		for _, n := range layer {
+			width = math.Max(width, n.x+n.width/2)
+		}
+	}
+	layout.Width, layout.Height = width, top
+
+	position := func(n *layoutNode, dy float64) Point {
+		p := Point{n.x, ys[n.layer] + dy}
+		if opts.Direction == "LR" {
+			p.X, p.Y = p.Y, p.X
+		}
+		return p
+	}
+
+	for _, layer := range layers {
+		for _, n := range layer {
+			if n.node != nil {
+				p := position(n, 0)
+				n.node.X, n.node.Y = p.X, p.Y
+			}
+		}
+	}
+	for i, chain := range chains {
+		points := []Point{position(chain[0], chain[0].height/2)}
+		for _, n := range chain[1 : len(chain)-1] {
+			points = append(points, position(n, 0))
+		}
+		head := chain[len(chain)-1]
+		layout.Edges[i].Points = append(points, position(head, -head.height/2))
+	}
+	if opts.Direction == "LR" {
+		layout.Width, layout.Height = layout.Height, layout.Width
+	}
+
+	return layout, nil
+}
+
+// orderLayers orders the nodes of each layer to reduce edge crossings.
+func orderLayers(layers [][]*layoutNode, heuristic CrossingHeuristic, iterations int) {
+	setOrder := func() {
+		for _, layer := range layers {
+			for i, n := range layer {
+				n.order = i
+			}
+		}
+	}
+	setOrder()
+
+	best := make([][]*layoutNode, len(layers))
+	save := func() {
+		for i, layer := range layers {
+			best[i] = append(best[i][:0], layer...)
+		}
+	}
+	save()
+	bestCrossings := crossings(layers)
+
+	for i := 0; i < iterations && bestCrossings > 0; i++ {
+		for l := 1; l < len(layers); l++ {
+			sortLayer(layers[l], heuristic, func(n *layoutNode) []*layoutNode { return n.up })
+		}
+		for l := len(layers) - 2; l >= 0; l-- {
+			sortLayer(layers[l], heuristic, func(n *layoutNode) []*layoutNode { return n.down })
+		}
+
+		if c := crossings(layers); c < bestCrossings {
+			bestCrossings = c
+			save()
+		}
+	}
+
+	copy(layers, best)
+	setOrder()
+}
+
+// sortLayer sorts the nodes of a layer by the position of their neighbours
+// in the adjacent layer. Nodes without neighbours keep their position.
+func sortLayer(layer []*layoutNode, heuristic CrossingHeuristic, neighbours func(*layoutNode) []*layoutNode) {
+	keys := make(map[*layoutNode]float64, len(layer))
+	for _, n := range layer {
+		orders := make([]float64, 0, len(neighbours(n)))
+		for _, neighbour := range neighbours(n) {
+			orders = append(orders, float64(neighbour.order))
+		}
+
+		switch {
+		case len(orders) == 0:
+			keys[n] = float64(n.order)
+		case heuristic == Median:
+			sort.Float64s(orders)
+			middle := len(orders) / 2
+			if len(orders)%2 == 0 {
+				keys[n] = (orders[middle-1] + orders[middle]) / 2
+			} else {
+				keys[n] = orders[middle]
+			}
+		default:
+			sum := 0.0
+			for _, order := range orders {
+				sum += order
+			}
+			keys[n] = sum / float64(len(orders))
+		}
+	}
+
+	sort.SliceStable(layer, func(i, j int) bool {
+		return keys[layer[i]] < keys[layer[j]]
+	})
+	for i, n := range layer {
+		n.order = i
+	}
+}
+
+// crossings return the number of edge crossings between consecutive layers.
+func crossings(layers [][]*layoutNode) int {
+	count := 0
+
+	for _, layer := range layers {
+		var edges [][2]int
+		for _, n := range layer {
+			for _, child := range n.down {
+				edges = append(edges, [2]int{n.order, child.order})
+			}
+		}
+		for i := range edges {
+			for j := i + 1; j < len(edges); j++ {
+				a, b := edges[i], edges[j]
+				if (a[0] < b[0] && a[1] > b[1]) || (a[0] > b[0] && a[1] < b[1]) {
+					count++
+				}
+			}
+		}
+	}
+
+	return count
+}
+
+// placeLayers assigns the horizontal coordinate of every node, keeping the
+// order of each layer. Starting from every layer packed to the left, it
+// repeatedly moves the nodes of each layer as close as possible to the mean
+// position of their neighbours in the previous layer, sweeping down, and
+// then in the next layer, sweeping up.
+func placeLayers(layers [][]*layoutNode, spacing float64, iterations int) {
+	for _, layer := range layers {
+		x := 0.0
+		for i, n := range layer {
+			if i > 0 {
+				x += spacing
+			}
+			n.x = x + n.width/2
+			x += n.width
+		}
+	}
+
+	for i := 0; i < iterations; i++ {
+		for l := 1; l < len(layers); l++ {
+			placeLayer(layers[l], spacing, func(n *layoutNode) []*layoutNode { return n.up })
+		}
+		for l := len(layers) - 2; l >= 0; l-- {
+			placeLayer(layers[l], spacing, func(n *layoutNode) []*layoutNode { return n.down })
+		}
+	}
+
+	left := math.Inf(1)
+	for _, layer := range layers {
+		if len(layer) > 0 {
+			left = math.Min(left, layer[0].x-layer[0].width/2)
+		}
+	}
+	for _, layer := range layers {
+		for _, n := range layer {
+			n.x -= left
+		}
+	}
+}
+
+// placeLayer moves the nodes of a layer as close as possible to the mean
+// position of their neighbours, in the least squares sense, without
+// changing their order nor overlapping.
+//
+// Subtracting from each node the minimum offset it has from the first one
+// turns the problem into an isotonic regression, solved with the pool
+// adjacent violators algorithm.
+func placeLayer(layer []*layoutNode, spacing float64, neighbours func(*layoutNode) []*layoutNode) {
+	if len(layer) == 0 {
+		return
+	}
+
+	offsets := make([]float64, len(layer))
+	for i := 1; i < len(layer); i++ {
+		offsets[i] = offsets[i-1] + layer[i-1].width/2 + spacing + layer[i].width/2
+	}
+
+	type block struct {
+		sum   float64
+		count int
+	}
+	var blocks []block
+	for i, n := range layer {
+		target := n.x
+		if len(neighbours(n)) > 0 {
+			target = 0
+			for _, neighbour := range neighbours(n) {
+				target += neighbour.x
+			}
+			target /= float64(len(neighbours(n)))
+		}
+
+		blocks = append(blocks, block{target - offsets[i], 1})
+		for len(blocks) > 1 {
+			last, previous := blocks[len(blocks)-1], blocks[len(blocks)-2]
+			if previous.sum/float64(previous.count) <= last.sum/float64(last.count) {
+				break
+			}
+			blocks = blocks[:len(blocks)-1]
+			blocks[len(blocks)-1] = block{previous.sum + last.sum, previous.count + last.count}
+		}
+	}
+
+	i := 0
+	for _, b := range blocks {
+		mean := b.sum / float64(b.count)
+		for j := 0; j < b.count; j++ {
+			layer[i].x = mean + offsets[i]
+			i++
+		}
+	}
+}
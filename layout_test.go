@@ -0,0 +1,251 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+// layoutNodes return the nodes of a layout by vertex ID.
+func layoutNodes(layout *dag.Layout) map[string]*dag.LayoutNode {
+	nodes := make(map[string]*dag.LayoutNode, len(layout.Nodes))
+	for _, node := range layout.Nodes {
+		nodes[node.ID] = node
+	}
+
+	return nodes
+}
+
+func TestDAG_Layout(t *testing.T) {
+	dag1, _ := newTestDAG(t,
+		[]string{"a", "b", "c", "d", "e", "f"},
+		[][2]string{{"a", "c"}, {"b", "d"}, {"a", "d"}, {"b", "c"}, {"c", "e"}, {"a", "e"}, {"d", "f"}},
+	)
+
+	layout, err := dag1.Layout(nil)
+	if err != nil {
+		t.Fatalf("Can't compute layout: %s", err)
+	}
+
+	if len(layout.Nodes) != dag1.Order() {
+		t.Fatalf("Layout expected to have %d nodes but got %d", dag1.Order(), len(layout.Nodes))
+	}
+	if len(layout.Edges) != dag1.Size() {
+		t.Fatalf("Layout expected to have %d edges but got %d", dag1.Size(), len(layout.Edges))
+	}
+
+	for _, node := range layout.Nodes {
+		depth, err := dag1.Depth(node.Vertex)
+		if err != nil {
+			t.Fatalf("Can't get vertex depth: %s", err)
+		}
+		if node.Layer != depth {
+			t.Fatalf("Expected vertex %s layer to be %d but got %d", node.ID, depth, node.Layer)
+		}
+		if node.Label != node.ID {
+			t.Fatalf("Expected vertex %s label to be its ID but got %q", node.ID, node.Label)
+		}
+		if node.X-node.Width/2 < 0 || node.Y-node.Height/2 < 0 ||
+			node.X+node.Width/2 > layout.Width || node.Y+node.Height/2 > layout.Height {
+			t.Fatalf("Vertex %s is out of the %vx%v layout: %+v", node.ID, layout.Width, layout.Height, node)
+		}
+
+		for _, other := range layout.Nodes {
+			if other == node || other.Layer != node.Layer {
+				continue
+			}
+			if math.Abs(other.X-node.X) < (other.Width+node.Width)/2 {
+				t.Fatalf("Vertices %s and %s overlap", node.ID, other.ID)
+			}
+		}
+	}
+
+	nodes := layoutNodes(layout)
+	for _, edge := range layout.Edges {
+		tail, head := nodes[edge.Tail.ID], nodes[edge.Head.ID]
+		expectedPoints := head.Layer - tail.Layer + 1
+		if len(edge.Points) != expectedPoints {
+			t.Fatalf("Edge (%s,%s) expected to have %d points but got %d", tail.ID, head.ID, expectedPoints, len(edge.Points))
+		}
+		first, last := edge.Points[0], edge.Points[len(edge.Points)-1]
+		if first.X != tail.X || first.Y != tail.Y+tail.Height/2 {
+			t.Fatalf("Edge (%s,%s) expected to start at the bottom of its tail but starts at %v", tail.ID, head.ID, first)
+		}
+		if last.X != head.X || last.Y != head.Y-head.Height/2 {
+			t.Fatalf("Edge (%s,%s) expected to end at the top of its head but ends at %v", tail.ID, head.ID, last)
+		}
+		for i := 1; i < len(edge.Points); i++ {
+			if edge.Points[i].Y <= edge.Points[i-1].Y {
+				t.Fatalf("Edge (%s,%s) expected to go down but got %v", tail.ID, head.ID, edge.Points)
+			}
+		}
+	}
+}
+
+func TestDAG_Layout_CrossingMinimisation(t *testing.T) {
+	for _, heuristic := range []dag.CrossingHeuristic{dag.Barycenter, dag.Median} {
+		dag1, _ := newTestDAG(t,
+			[]string{"a", "b", "c", "d", "e"},
+			[][2]string{{"a", "d"}, {"a", "e"}, {"b", "c"}},
+		)
+
+		layout, err := dag1.Layout(&dag.LayoutOptions{Heuristic: heuristic})
+		if err != nil {
+			t.Fatalf("Can't compute layout: %s", err)
+		}
+
+		nodes := layoutNodes(layout)
+		if nodes["a"].X < nodes["b"].X != (nodes["d"].X < nodes["c"].X) {
+			t.Fatalf("Expected no edge crossings with the %s heuristic but got %+v", heuristic, layout.Nodes)
+		}
+	}
+}
+
+func TestDAG_Layout_Alignment(t *testing.T) {
+	dag1, _ := newTestDAG(t,
+		[]string{"a", "b", "c"},
+		[][2]string{{"a", "b"}, {"b", "c"}},
+	)
+
+	layout, err := dag1.Layout(&dag.LayoutOptions{
+		Size: func(v *dag.Vertex) (float64, float64) {
+			if v.ID == "b" {
+				return 100, 40
+			}
+			return 20, 20
+		},
+		LayerSpacing: 10,
+	})
+	if err != nil {
+		t.Fatalf("Can't compute layout: %s", err)
+	}
+
+	nodes := layoutNodes(layout)
+	for _, id := range []string{"a", "b", "c"} {
+		if nodes[id].X != 50 {
+			t.Fatalf("Expected vertex %s to be centered at x 50 but got %v", id, nodes[id].X)
+		}
+	}
+	if nodes["b"].Width != 100 || nodes["b"].Height != 40 {
+		t.Fatalf("Expected vertex b to be 100x40 but got %vx%v", nodes["b"].Width, nodes["b"].Height)
+	}
+	if layout.Width != 100 || layout.Height != 100 {
+		t.Fatalf("Expected layout to be 100x100 but got %vx%v", layout.Width, layout.Height)
+	}
+}
+
+func TestDAG_Layout_LeftToRight(t *testing.T) {
+	dag1, _ := newTestDAG(t,
+		[]string{"a", "b", "c"},
+		[][2]string{{"a", "b"}, {"a", "c"}},
+	)
+
+	layout, err := dag1.Layout(&dag.LayoutOptions{
+		Direction: "LR",
+		Size: func(v *dag.Vertex) (float64, float64) {
+			return 40, 20
+		},
+	})
+	if err != nil {
+		t.Fatalf("Can't compute layout: %s", err)
+	}
+
+	nodes := layoutNodes(layout)
+	if nodes["a"].X != 20 || nodes["b"].X != 110 || nodes["c"].X != 110 {
+		t.Fatalf("Expected layers at x 20 and 110 but got %+v", layout.Nodes)
+	}
+	if nodes["b"].Y == nodes["c"].Y {
+		t.Fatalf("Expected vertices b and c at different heights but got %+v", layout.Nodes)
+	}
+	if layout.Width != 130 || layout.Height != 60 {
+		t.Fatalf("Expected layout to be 130x60 but got %vx%v", layout.Width, layout.Height)
+	}
+
+	edge := layout.Edges[0]
+	if edge.Points[0].X != 40 || edge.Points[1].X != 90 {
+		t.Fatalf("Expected edge to go from x 40 to 90 but got %v", edge.Points)
+	}
+}
+
+func TestDAG_Layout_JSON(t *testing.T) {
+	dag1, _ := newTestDAG(t,
+		[]string{"a", "b"},
+		[][2]string{{"a", "b"}},
+	)
+
+	layout, err := dag1.Layout(&dag.LayoutOptions{
+		Size: func(v *dag.Vertex) (float64, float64) {
+			return 20, 10
+		},
+	})
+	if err != nil {
+		t.Fatalf("Can't compute layout: %s", err)
+	}
+
+	data, err := json.Marshal(layout)
+	if err != nil {
+		t.Fatalf("Can't marshal layout: %s", err)
+	}
+
+	expected := `{"width":20,"height":70,"nodes":[` +
+		`{"id":"a","label":"a","layer":0,"x":10,"y":5,"width":20,"height":10},` +
+		`{"id":"b","label":"b","layer":1,"x":10,"y":65,"width":20,"height":10}],` +
+		`"edges":[{"points":[{"x":10,"y":10},{"x":10,"y":60}]}]}`
+	if string(data) != expected {
+		t.Fatalf("Expected layout JSON to be %s but got %s", expected, data)
+	}
+}
+
+func TestDAG_Layout_Errors(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"a", "b"},
+		[][2]string{{"a", "b"}},
+	)
+
+	_, err := dag1.Layout(&dag.LayoutOptions{Direction: "BT"})
+	if err == nil {
+		t.Fatalf("Direction is invalid, Layout should fail but it doesn't")
+	}
+
+	vertices["b"].Children.Add(vertices["a"])
+	vertices["a"].Parents.Add(vertices["b"])
+
+	_, err = dag1.Layout(nil)
+	var cycleErr *dag.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected error to be a *dag.CycleError but got %v", err)
+	}
+}
+
+func TestCrossingHeuristic_String(t *testing.T) {
+	tests := map[dag.CrossingHeuristic]string{
+		dag.Barycenter:            "barycenter",
+		dag.Median:                "median",
+		dag.CrossingHeuristic(42): "CrossingHeuristic(42)",
+	}
+
+	for heuristic, expected := range tests {
+		if heuristic.String() != expected {
+			t.Fatalf("Expected heuristic string to be %q but got %q", expected, heuristic.String())
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag
+
+import (
+	"fmt"
+)
+
+// ParseError is returned when reading a graph from a file fails, pointing to
+// the position of the problem in the input.
+//
+// Err is the underlying error, such as a syntax error or a *CycleError.
+type ParseError struct {
+	// File is the name of the input file, if known.
+	File string
+
+	// Line and Column start at 1. Column is zero if unknown.
+	Line   int
+	Column int
+
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	position := fmt.Sprintf("%d", e.Line)
+	if e.Column > 0 {
+		position += fmt.Sprintf(":%d", e.Column)
+	}
+	if e.File != "" {
+		position = e.File + ":" + position
+	}
+
+	return position + ": " + e.Err.Error()
+}
+
+// Unwrap return the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,46 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+func TestParseError(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		err      *dag.ParseError
+		expected string
+	}{
+		{&dag.ParseError{Line: 3, Err: errBoom}, "3: boom"},
+		{&dag.ParseError{Line: 3, Column: 7, Err: errBoom}, "3:7: boom"},
+		{&dag.ParseError{File: "a.dot", Line: 3, Column: 7, Err: errBoom}, "a.dot:3:7: boom"},
+	}
+
+	for _, test := range tests {
+		if test.err.Error() != test.expected {
+			t.Fatalf("Expected error message to be %q but got %q", test.expected, test.err.Error())
+		}
+		if !errors.Is(test.err, errBoom) {
+			t.Fatalf("Expected error to wrap %q", errBoom)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// PathIterator walks the paths between two vertices one at a time, as
+// returned by AllPaths.
+//
+// Call Next to move to the next path, and Path to get it:
+//
+//	for paths.Next() {
+//		fmt.Println(paths.Path())
+//	}
+type PathIterator struct {
+	to *Vertex
+
+	// canReach holds the vertices from which there is a path to to.
+	canReach map[*Vertex]bool
+
+	// stack holds the current path, with the index of the next child to
+	// visit from each of its vertices.
+	stack []pathFrame
+	path  []*Vertex
+
+	// self is true until the path from to to itself is returned.
+	self bool
+}
+
+// pathFrame is a vertex of the current path of a PathIterator.
+type pathFrame struct {
+	vertex   *Vertex
+	children []interface{}
+	next     int
+}
+
+// AllPaths return an iterator over every path from one vertex to another,
+// in the order given by Children. If both are the same vertex, the only path
+// holds just that vertex.
+//
+// Paths are found lazily with a depth first search, which only visits the
+// vertices that lead to the target, so each path takes O(V) time no matter
+// how many there are. The graph must not be modified while iterating.
+//
+// It returns a *CycleError if the graph is not acyclic, as the search would
+// otherwise never end.
+func (d *DAG) AllPaths(from *Vertex, to *Vertex) (*PathIterator, error) {
+	if !d.containsVertex(from) {
+		return nil, fmt.Errorf("vertex %s not found in the graph", from.ID)
+	}
+	if !d.containsVertex(to) {
+		return nil, fmt.Errorf("vertex %s not found in the graph", to.ID)
+	}
+
+	_, err := d.TopologicalSort()
+	if err != nil {
+		return nil, err
+	}
+
+	it := &PathIterator{to: to}
+	if from == to {
+		it.self = true
+		return it, nil
+	}
+
+	ancestors, _ := d.Ancestors(to, nil)
+	it.canReach = make(map[*Vertex]bool, len(ancestors))
+	for _, ancestor := range ancestors {
+		it.canReach[ancestor] = true
+	}
+	if it.canReach[from] {
+		it.stack = []pathFrame{{vertex: from, children: from.Children.Values()}}
+	}
+
+	return it, nil
+}
+
+// Next moves to the next path, and return false when there are no more.
+func (it *PathIterator) Next() bool {
+	it.path = nil
+
+	if it.self {
+		it.self = false
+		it.path = []*Vertex{it.to}
+		return true
+	}
+
+	for len(it.stack) > 0 {
+		top := &it.stack[len(it.stack)-1]
+		if top.next == len(top.children) {
+			it.stack = it.stack[:len(it.stack)-1]
+			continue
+		}
+
+		child := top.children[top.next].(*Vertex)
+		top.next++
+
+		switch {
+		case child == it.to:
+			it.path = make([]*Vertex, 0, len(it.stack)+1)
+			for _, frame := range it.stack {
+				it.path = append(it.path, frame.vertex)
+			}
+			it.path = append(it.path, child)
+			return true
+		case it.canReach[child]:
+			it.stack = append(it.stack, pathFrame{vertex: child, children: child.Children.Values()})
+		}
+	}
+
+	return false
+}
+
+// Path return the current path, from the first vertex to the last one.
+func (it *PathIterator) Path() []*Vertex {
+	return it.path
+}
+
+// CountPaths return the number of paths from one vertex to another. If both
+// are the same vertex, there is one path.
+//
+// Paths are counted exactly, whatever their number, by adding the counts
+// of the parents of every vertex in topological order, in O(V+E) big
+// integer additions. It returns a *CycleError if the graph is not acyclic.
+func (d *DAG) CountPaths(from *Vertex, to *Vertex) (*big.Int, error) {
+	if !d.containsVertex(from) {
+		return nil, fmt.Errorf("vertex %s not found in the graph", from.ID)
+	}
+	if !d.containsVertex(to) {
+		return nil, fmt.Errorf("vertex %s not found in the graph", to.ID)
+	}
+
+	sorted, err := d.TopologicalSortStable()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[*Vertex]*big.Int{from: big.NewInt(1)}
+	for _, vertex := range sorted {
+		if vertex == to {
+			break
+		}
+		count, found := counts[vertex]
+		if !found {
+			continue
+		}
+		for _, child := range vertex.Children.Values() {
+			child := child.(*Vertex)
+			if counts[child] == nil {
+				counts[child] = new(big.Int)
+			}
+			counts[child].Add(counts[child], count)
+		}
+	}
+
+	if counts[to] == nil {
+		return new(big.Int), nil
+	}
+
+	return counts[to], nil
+}
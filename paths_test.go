@@ -0,0 +1,191 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag_test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+func TestDAG_AllPaths(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"a", "b", "c", "d", "e", "x"},
+		[][2]string{
+			{"a", "b"}, {"a", "c"}, {"a", "x"}, {"b", "d"}, {"c", "d"},
+			{"b", "e"}, {"d", "e"}, {"a", "e"}, {"e", "x"},
+		},
+	)
+
+	paths, err := dag1.AllPaths(vertices["a"], vertices["e"])
+	if err != nil {
+		t.Fatalf("Can't get paths: %s", err)
+	}
+
+	var got [][]string
+	for paths.Next() {
+		got = append(got, vertexIDs(paths.Path()))
+	}
+	expected := [][]string{
+		{"a", "b", "d", "e"},
+		{"a", "b", "e"},
+		{"a", "c", "d", "e"},
+		{"a", "e"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected paths to be %v but got %v", expected, got)
+	}
+	if paths.Next() || paths.Path() != nil {
+		t.Fatalf("Expected no more paths but got %v", vertexIDs(paths.Path()))
+	}
+
+	count, err := dag1.CountPaths(vertices["a"], vertices["e"])
+	if err != nil {
+		t.Fatalf("Can't count paths: %s", err)
+	}
+	if count.Int64() != int64(len(expected)) {
+		t.Fatalf("Expected %d paths but got %s", len(expected), count)
+	}
+}
+
+func TestDAG_AllPaths_Trivial(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"a", "b", "c"},
+		[][2]string{{"a", "b"}},
+	)
+
+	tests := []struct {
+		from     string
+		to       string
+		expected [][]string
+	}{
+		{"a", "a", [][]string{{"a"}}},
+		{"a", "b", [][]string{{"a", "b"}}},
+		{"b", "a", nil},
+		{"a", "c", nil},
+	}
+
+	for _, test := range tests {
+		paths, err := dag1.AllPaths(vertices[test.from], vertices[test.to])
+		if err != nil {
+			t.Fatalf("Can't get paths: %s", err)
+		}
+
+		var got [][]string
+		for paths.Next() {
+			got = append(got, vertexIDs(paths.Path()))
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Fatalf("Expected paths from %s to %s to be %v but got %v", test.from, test.to, test.expected, got)
+		}
+
+		count, err := dag1.CountPaths(vertices[test.from], vertices[test.to])
+		if err != nil {
+			t.Fatalf("Can't count paths: %s", err)
+		}
+		if count.Int64() != int64(len(test.expected)) {
+			t.Fatalf("Expected %d paths from %s to %s but got %s", len(test.expected), test.from, test.to, count)
+		}
+	}
+
+	_, err := dag1.AllPaths(vertices["a"], dag.NewVertex("b", nil))
+	if err == nil {
+		t.Fatalf("Vertex doesn't belong to the graph, AllPaths should fail but it doesn't")
+	}
+	_, err = dag1.CountPaths(dag.NewVertex("a", nil), vertices["b"])
+	if err == nil {
+		t.Fatalf("Vertex doesn't belong to the graph, CountPaths should fail but it doesn't")
+	}
+}
+
+func TestDAG_CountPaths_Big(t *testing.T) {
+	// A ladder of 100 diamonds has 2^100 paths from its top to its bottom.
+	dag1 := dag.NewDAG()
+
+	previous := dag.NewVertex("0", nil)
+	err := dag1.AddVertex(previous)
+	if err != nil {
+		t.Fatalf("Can't add vertex to DAG: %s", err)
+	}
+	first := previous
+	for i := 1; i <= 100; i++ {
+		left := dag.NewVertex(fmt.Sprintf("%d-left", i), nil)
+		right := dag.NewVertex(fmt.Sprintf("%d-right", i), nil)
+		next := dag.NewVertex(fmt.Sprint(i), nil)
+		for _, vertex := range []*dag.Vertex{left, right, next} {
+			err := dag1.AddVertex(vertex)
+			if err != nil {
+				t.Fatalf("Can't add vertex to DAG: %s", err)
+			}
+		}
+		for _, edge := range [][2]*dag.Vertex{{previous, left}, {previous, right}, {left, next}, {right, next}} {
+			err := dag1.AddEdge(edge[0], edge[1])
+			if err != nil {
+				t.Fatalf("Can't add edge to DAG: %s", err)
+			}
+		}
+		previous = next
+	}
+
+	count, err := dag1.CountPaths(first, previous)
+	if err != nil {
+		t.Fatalf("Can't count paths: %s", err)
+	}
+
+	expected := new(big.Int).Lsh(big.NewInt(1), 100)
+	if count.Cmp(expected) != 0 {
+		t.Fatalf("Expected %s paths but got %s", expected, count)
+	}
+
+	// Paths are found lazily, so the first ones come at once.
+	paths, err := dag1.AllPaths(first, previous)
+	if err != nil {
+		t.Fatalf("Can't get paths: %s", err)
+	}
+	for i := 0; i < 3; i++ {
+		if !paths.Next() {
+			t.Fatalf("Expected path %d to be found", i)
+		}
+		if len(paths.Path()) != 201 {
+			t.Fatalf("Expected path of 201 vertices but got %d", len(paths.Path()))
+		}
+	}
+}
+
+func TestDAG_CountPaths_Cycle(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"a", "b"},
+		[][2]string{{"a", "b"}},
+	)
+	vertices["b"].Children.Add(vertices["a"])
+	vertices["a"].Parents.Add(vertices["b"])
+
+	_, err := dag1.CountPaths(vertices["a"], vertices["b"])
+	var cycleErr *dag.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected error to be a *dag.CycleError but got %v", err)
+	}
+
+	_, err = dag1.AllPaths(vertices["a"], vertices["b"])
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected error to be a *dag.CycleError but got %v", err)
+	}
+}
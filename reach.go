@@ -0,0 +1,365 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// reachLabelings is the number of interval labelings of a reachability
+// index. Each one is computed with a different traversal order, and rules
+// out a different set of unreachable pairs.
+const reachLabelings = 5
+
+// reachLabel is the label of a vertex in a reachability index.
+//
+// Level is greater than the level of every parent of the vertex. Each
+// interval contains the intervals of every child of the vertex. So if a
+// vertex can reach another one, its level is lower and its intervals
+// contain the ones of the other vertex.
+type reachLabel struct {
+	level     int
+	intervals [reachLabelings][2]int
+
+	// children holds the labels of the children of the vertex, so that
+	// searches don't copy the Children of every vertex they visit.
+	children []*reachLabel
+
+	// visited is the number of the last search that visited the vertex.
+	visited int
+}
+
+// contains return whether the intervals of the label contain the ones of
+// another label.
+func (l *reachLabel) contains(other *reachLabel) bool {
+	for i := range l.intervals {
+		if other.intervals[i][0] < l.intervals[i][0] || other.intervals[i][1] > l.intervals[i][1] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// reachIndex is a reachability index of a graph, following the GRAIL
+// interval labeling scheme by Yildirim, Chaoji and Zaki.
+//
+// Labels tell in constant time that most pairs of vertices are not
+// reachable, and prune the depth first search needed for the other ones.
+type reachIndex struct {
+	labels map[*Vertex]*reachLabel
+
+	// nextRank is the rank given to the next vertex added to the graph,
+	// after the ranks of all the labeled vertices.
+	nextRank int
+
+	// deletions counts the edges deleted since the index was built. They
+	// leave the labels correct but less selective, so the index is rebuilt
+	// once they pile up.
+	deletions int
+
+	// searches counts the searches run by isReachable, to mark the labels
+	// visited by each one.
+	searches int
+}
+
+// IsReachable return whether there is a path from one vertex to another in
+// the graph. A vertex can always reach itself.
+//
+// Queries are answered with a reachability index, built on the first query
+// and maintained by AddVertex, DeleteVertex, AddEdge and DeleteEdge. Most
+// unreachable pairs are ruled out in constant time, and the others, as well
+// as the reachable ones, with a search pruned by the index. It is also used
+// by AddEdge to skip the cycle check of most new edges.
+//
+// If the Parents or Children of a vertex are modified by hand, call
+// BuildReachabilityIndex to rebuild the index.
+func (d *DAG) IsReachable(from *Vertex, to *Vertex) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.containsVertex(from) {
+		return false, fmt.Errorf("vertex %s not found in the graph", from.ID)
+	}
+	if !d.containsVertex(to) {
+		return false, fmt.Errorf("vertex %s not found in the graph", to.ID)
+	}
+
+	if d.reach == nil || d.reach.deletions > d.vertices.Size() {
+		if err := d.buildReachIndex(); err != nil {
+			return false, err
+		}
+	}
+
+	return d.reach.isReachable(from, to), nil
+}
+
+// BuildReachabilityIndex builds, or rebuilds, the index used by IsReachable.
+// It returns a *CycleError if the graph is not acyclic.
+func (d *DAG) BuildReachabilityIndex() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.buildReachIndex()
+}
+
+// buildReachIndex builds the reachability index of the graph.
+func (d *DAG) buildReachIndex() error {
+	d.reach = nil
+
+	sorted, err := d.TopologicalSortStable()
+	if err != nil {
+		return err
+	}
+
+	index := &reachIndex{labels: make(map[*Vertex]*reachLabel, len(sorted))}
+	for _, vertex := range sorted {
+		label := &reachLabel{}
+		for _, parent := range vertex.Parents.Values() {
+			if parentLabel, found := index.labels[parent.(*Vertex)]; found && parentLabel.level >= label.level {
+				label.level = parentLabel.level + 1
+			}
+		}
+		index.labels[vertex] = label
+	}
+	for _, vertex := range sorted {
+		for _, child := range vertex.Children.Values() {
+			index.addChild(vertex, child.(*Vertex))
+		}
+	}
+
+	// The first labeling follows the topological order, and the other ones
+	// pseudo-random orders, always the same for a given graph.
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < reachLabelings; i++ {
+		shuffle := func(values []interface{}) {}
+		if i > 0 {
+			shuffle = func(values []interface{}) {
+				random.Shuffle(len(values), func(i, j int) {
+					values[i], values[j] = values[j], values[i]
+				})
+			}
+		}
+		index.label(sorted, i, shuffle)
+	}
+	index.nextRank = len(sorted) + 1
+
+	d.reach = index
+
+	return nil
+}
+
+// label computes the intervals of a labeling with a post-order depth first
+// search, starting from the vertices and visiting children in the order left
+// by shuffle.
+//
+// The interval of a vertex goes from the lowest rank of its descendants to
+// its own rank.
+func (r *reachIndex) label(vertices []*Vertex, labeling int, shuffle func([]interface{})) {
+	type frame struct {
+		vertex   *Vertex
+		children []interface{}
+		next     int
+	}
+
+	roots := make([]interface{}, len(vertices))
+	for i, vertex := range vertices {
+		roots[i] = vertex
+	}
+	shuffle(roots)
+
+	children := func(vertex *Vertex) []interface{} {
+		values := vertex.Children.Values()
+		shuffle(values)
+		return values
+	}
+
+	visited := make(map[*Vertex]bool, len(vertices))
+	rank := 0
+
+	for _, root := range roots {
+		root := root.(*Vertex)
+		if visited[root] {
+			continue
+		}
+
+		visited[root] = true
+		r.labels[root].intervals[labeling][0] = len(vertices) + 1
+		stack := []*frame{{vertex: root, children: children(root)}}
+
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			label := r.labels[top.vertex]
+
+			if top.next == len(top.children) {
+				rank++
+				label.intervals[labeling][1] = rank
+				if rank < label.intervals[labeling][0] {
+					label.intervals[labeling][0] = rank
+				}
+				stack = stack[:len(stack)-1]
+				if len(stack) > 0 {
+					parent := r.labels[stack[len(stack)-1].vertex]
+					if label.intervals[labeling][0] < parent.intervals[labeling][0] {
+						parent.intervals[labeling][0] = label.intervals[labeling][0]
+					}
+				}
+				continue
+			}
+
+			child := top.children[top.next].(*Vertex)
+			top.next++
+
+			childLabel, found := r.labels[child]
+			if !found {
+				continue
+			}
+			if visited[child] {
+				if childLabel.intervals[labeling][0] < label.intervals[labeling][0] {
+					label.intervals[labeling][0] = childLabel.intervals[labeling][0]
+				}
+				continue
+			}
+
+			visited[child] = true
+			childLabel.intervals[labeling][0] = len(vertices) + 1
+			stack = append(stack, &frame{vertex: child, children: children(child)})
+		}
+	}
+}
+
+// isReachable return whether there is a path from one vertex to another,
+// with a depth first search skipping the vertices whose label rules out a
+// path to the target.
+func (r *reachIndex) isReachable(from *Vertex, to *Vertex) bool {
+	if from == to {
+		return true
+	}
+
+	source, target := r.labels[from], r.labels[to]
+	canReach := func(label *reachLabel) bool {
+		return label.level < target.level && label.contains(target)
+	}
+	if !canReach(source) {
+		return false
+	}
+
+	r.searches++
+	source.visited = r.searches
+	stack := []*reachLabel{source}
+	for len(stack) > 0 {
+		label := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, child := range label.children {
+			if child == target {
+				return true
+			}
+			if child.visited == r.searches || !canReach(child) {
+				continue
+			}
+			child.visited = r.searches
+			stack = append(stack, child)
+		}
+	}
+
+	return false
+}
+
+// addVertex labels a vertex added to the graph. It can't reach any other
+// vertex yet, so it gets a new rank of its own.
+func (r *reachIndex) addVertex(vertex *Vertex) {
+	label := &reachLabel{}
+	for i := range label.intervals {
+		label.intervals[i] = [2]int{r.nextRank, r.nextRank}
+	}
+	r.nextRank++
+
+	r.labels[vertex] = label
+}
+
+// addEdge updates the labels after adding an edge to the graph: the levels
+// of the head and its descendants are raised above the level of the tail,
+// and the intervals of the tail and its ancestors widened to contain the
+// ones of the head.
+func (r *reachIndex) addEdge(tail *Vertex, head *Vertex) {
+	r.addChild(tail, head)
+
+	levels := []*reachLabel{r.labels[tail]}
+	for len(levels) > 0 {
+		parent := levels[0]
+		levels = levels[1:]
+
+		for _, label := range parent.children {
+			if label.level <= parent.level {
+				label.level = parent.level + 1
+				levels = append(levels, label)
+			}
+		}
+	}
+
+	headLabel := r.labels[head]
+	queue := []*Vertex{tail}
+	for len(queue) > 0 {
+		vertex := queue[0]
+		queue = queue[1:]
+
+		label := r.labels[vertex]
+		if label.contains(headLabel) {
+			continue
+		}
+		for i := range label.intervals {
+			if headLabel.intervals[i][0] < label.intervals[i][0] {
+				label.intervals[i][0] = headLabel.intervals[i][0]
+			}
+			if headLabel.intervals[i][1] > label.intervals[i][1] {
+				label.intervals[i][1] = headLabel.intervals[i][1]
+			}
+		}
+		for _, parent := range vertex.Parents.Values() {
+			queue = append(queue, parent.(*Vertex))
+		}
+	}
+}
+
+// addChild records an edge in the children of the label of its tail.
+func (r *reachIndex) addChild(tail *Vertex, head *Vertex) {
+	label, headLabel := r.labels[tail], r.labels[head]
+	if label == nil || headLabel == nil {
+		return
+	}
+
+	label.children = append(label.children, headLabel)
+}
+
+// removeChild removes an edge deleted from the graph from the children of
+// the label of its tail. The labels stay correct, only less selective.
+func (r *reachIndex) removeChild(tail *Vertex, head *Vertex) {
+	label, headLabel := r.labels[tail], r.labels[head]
+	if label == nil {
+		return
+	}
+
+	for i, child := range label.children {
+		if child == headLabel {
+			label.children = append(label.children[:i], label.children[i+1:]...)
+			return
+		}
+	}
+}
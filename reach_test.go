@@ -0,0 +1,314 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag_test
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+// checkReachability checks IsReachable agrees with Descendants for every
+// pair of vertices of the graph.
+func checkReachability(t *testing.T, dag1 *dag.DAG) {
+	t.Helper()
+
+	for _, from := range dag1.Vertices() {
+		descendants, err := dag1.Descendants(from, nil)
+		if err != nil {
+			t.Fatalf("Can't get descendants: %s", err)
+		}
+		reachable := map[*dag.Vertex]bool{from: true}
+		for _, descendant := range descendants {
+			reachable[descendant] = true
+		}
+
+		for _, to := range dag1.Vertices() {
+			ok, err := dag1.IsReachable(from, to)
+			if err != nil {
+				t.Fatalf("Can't check reachability: %s", err)
+			}
+			if ok != reachable[to] {
+				t.Fatalf("Expected reachability from %s to %s to be %v but got %v", from.ID, to.ID, reachable[to], ok)
+			}
+		}
+	}
+}
+
+func TestDAG_IsReachable(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"1", "2", "3", "4", "5"},
+		[][2]string{{"1", "2"}, {"2", "3"}, {"1", "4"}},
+	)
+
+	tests := []struct {
+		from     string
+		to       string
+		expected bool
+	}{
+		{"1", "3", true},
+		{"1", "1", true},
+		{"3", "1", false},
+		{"4", "3", false},
+		{"5", "1", false},
+	}
+
+	for _, test := range tests {
+		ok, err := dag1.IsReachable(vertices[test.from], vertices[test.to])
+		if err != nil {
+			t.Fatalf("Can't check reachability: %s", err)
+		}
+		if ok != test.expected {
+			t.Fatalf("Expected reachability from %s to %s to be %v but got %v", test.from, test.to, test.expected, ok)
+		}
+	}
+
+	_, err := dag1.IsReachable(vertices["1"], dag.NewVertex("2", nil))
+	if err == nil {
+		t.Fatalf("Vertex doesn't belong to the graph, IsReachable should fail but it doesn't")
+	}
+}
+
+func TestDAG_IsReachable_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	dag1 := dag.NewDAG()
+	var vertices []*dag.Vertex
+	for i := 0; i < 60; i++ {
+		vertex := dag.NewVertex(fmt.Sprint(i), nil)
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+		vertices = append(vertices, vertex)
+	}
+
+	// Edges always go from a lower to a higher number, so the graph stays
+	// acyclic.
+	addEdges := func(n int) {
+		for i := 0; i < n; i++ {
+			a, b := r.Intn(len(vertices)), r.Intn(len(vertices))
+			if a == b {
+				continue
+			}
+			if a > b {
+				a, b = b, a
+			}
+			if vertices[a].Children.Contains(vertices[b]) {
+				continue
+			}
+			err := dag1.AddEdge(vertices[a], vertices[b])
+			if err != nil {
+				t.Fatalf("Can't add edge to DAG: %s", err)
+			}
+		}
+	}
+
+	addEdges(80)
+	checkReachability(t, dag1)
+
+	// The index is maintained as the graph changes.
+	addEdges(40)
+	checkReachability(t, dag1)
+
+	for i := 0; i < 30; i++ {
+		vertex := vertices[r.Intn(len(vertices))]
+		children := vertex.Children.Values()
+		if len(children) == 0 {
+			continue
+		}
+		err := dag1.DeleteEdge(vertex, children[r.Intn(len(children))].(*dag.Vertex))
+		if err != nil {
+			t.Fatalf("Can't delete edge from DAG: %s", err)
+		}
+	}
+	checkReachability(t, dag1)
+
+	for i := 0; i < 5; i++ {
+		j := r.Intn(len(vertices))
+		err := dag1.DeleteVertex(vertices[j])
+		if err != nil {
+			t.Fatalf("Can't delete vertex from DAG: %s", err)
+		}
+		vertices = append(vertices[:j], vertices[j+1:]...)
+	}
+	for i := 60; i < 70; i++ {
+		vertex := dag.NewVertex(fmt.Sprint(i), nil)
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+		vertices = append(vertices, vertex)
+	}
+	addEdges(60)
+	checkReachability(t, dag1)
+}
+
+func TestDAG_IsReachable_AddEdgeCycle(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"1", "2", "3"},
+		[][2]string{{"1", "2"}, {"2", "3"}},
+	)
+
+	_, err := dag1.IsReachable(vertices["1"], vertices["3"])
+	if err != nil {
+		t.Fatalf("Can't check reachability: %s", err)
+	}
+
+	err = dag1.AddEdge(vertices["3"], vertices["1"])
+	var cycleErr *dag.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected error to be a *dag.CycleError but got %v", err)
+	}
+}
+
+func TestDAG_BuildReachabilityIndex(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"1", "2", "3"},
+		[][2]string{{"1", "2"}},
+	)
+
+	ok, err := dag1.IsReachable(vertices["2"], vertices["3"])
+	if err != nil || ok {
+		t.Fatalf("Expected 3 not to be reachable from 2 but got %v, %v", ok, err)
+	}
+
+	// Edges added by hand are only seen once the index is rebuilt.
+	vertices["2"].Children.Add(vertices["3"])
+	vertices["3"].Parents.Add(vertices["2"])
+
+	err = dag1.BuildReachabilityIndex()
+	if err != nil {
+		t.Fatalf("Can't build reachability index: %s", err)
+	}
+	ok, err = dag1.IsReachable(vertices["1"], vertices["3"])
+	if err != nil || !ok {
+		t.Fatalf("Expected 3 to be reachable from 1 but got %v, %v", ok, err)
+	}
+
+	vertices["3"].Children.Add(vertices["1"])
+	vertices["1"].Parents.Add(vertices["3"])
+
+	err = dag1.BuildReachabilityIndex()
+	var cycleErr *dag.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected error to be a *dag.CycleError but got %v", err)
+	}
+}
+
+func TestDAG_IsReachable_TransitiveDeletes(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"a", "c"}},
+	)
+	checkReachability(t, dag1)
+
+	// The index follows the edges removed by a reduction...
+	_, err := dag1.TransitiveReduction()
+	if err != nil {
+		t.Fatalf("Can't reduce DAG: %s", err)
+	}
+	err = dag1.DeleteEdge(vertices["b"], vertices["c"])
+	if err != nil {
+		t.Fatalf("Can't delete edge from DAG: %s", err)
+	}
+	checkReachability(t, dag1)
+
+	// ... and the ones added by a closure.
+	err = dag1.AddEdge(vertices["b"], vertices["c"])
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+	err = dag1.TransitiveClosure()
+	if err != nil {
+		t.Fatalf("Can't close DAG: %s", err)
+	}
+	err = dag1.DeleteEdge(vertices["c"], vertices["d"])
+	if err != nil {
+		t.Fatalf("Can't delete edge from DAG: %s", err)
+	}
+	err = dag1.DeleteVertex(vertices["b"])
+	if err != nil {
+		t.Fatalf("Can't delete vertex from DAG: %s", err)
+	}
+	checkReachability(t, dag1)
+}
+
+// newBenchmarkDAG creates a random graph of n vertices and 3n edges, which
+// always go from a lower to a higher vertex number.
+func newBenchmarkDAG(b *testing.B, n int) (*dag.DAG, []*dag.Vertex) {
+	b.Helper()
+
+	r := rand.New(rand.NewSource(1))
+
+	dag1 := dag.NewDAG()
+	vertices := make([]*dag.Vertex, n)
+	for i := range vertices {
+		vertices[i] = dag.NewVertex(fmt.Sprint(i), nil)
+		err := dag1.AddVertex(vertices[i])
+		if err != nil {
+			b.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+	}
+
+	// The index lets AddEdge skip the cycle check of most edges.
+	err := dag1.BuildReachabilityIndex()
+	if err != nil {
+		b.Fatalf("Can't build reachability index: %s", err)
+	}
+
+	for i := 0; i < 3*n; i++ {
+		a := r.Intn(n - 1)
+		span := n - a - 1
+		if span > 100 {
+			span = 100
+		}
+		c := a + 1 + r.Intn(span)
+		if vertices[a].Children.Contains(vertices[c]) {
+			continue
+		}
+		err := dag1.AddEdge(vertices[a], vertices[c])
+		if err != nil {
+			b.Fatalf("Can't add edge to DAG: %s", err)
+		}
+	}
+
+	return dag1, vertices
+}
+
+func BenchmarkDAG_IsReachable(b *testing.B) {
+	dag1, vertices := newBenchmarkDAG(b, 10000)
+
+	r := rand.New(rand.NewSource(2))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		from, to := vertices[r.Intn(len(vertices))], vertices[r.Intn(len(vertices))]
+		_, err := dag1.IsReachable(from, to)
+		if err != nil {
+			b.Fatalf("Can't check reachability: %s", err)
+		}
+	}
+}
+
+func BenchmarkDAG_AddEdge(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		newBenchmarkDAG(b, 10000)
+	}
+}
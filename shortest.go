@@ -0,0 +1,156 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrNoPath is returned, wrapped, when there is no path between two
+// vertices.
+var ErrNoPath = errors.New("no path")
+
+// Path is a path in a graph with its total weight.
+type Path struct {
+	Vertices []*Vertex
+	Weight   float64
+}
+
+// ShortestPath return the path with the lowest total weight from one vertex
+// to another, along with that weight.
+//
+// The weight of each edge is given by weight, or is its Weight if it is
+// nil, which is 1 unless set otherwise, so by default the path with the
+// fewest edges is returned. Weights may be negative. Edges are relaxed in
+// topological order, keeping the best one into each vertex, so it runs in
+// O(V+E) time. It returns an error wrapping ErrNoPath if to is not
+// reachable from from, and a *CycleError if the graph is not acyclic.
+func (d *DAG) ShortestPath(from *Vertex, to *Vertex, weight func(tail *Vertex, head *Vertex) float64) ([]*Vertex, float64, error) {
+	paths, err := d.KShortestPaths(from, to, 1, weight)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return paths[0].Vertices, paths[0].Weight, nil
+}
+
+// KShortestPaths return up to k paths with the lowest total weight from one
+// vertex to another, sorted by weight, as alternatives to the shortest
+// path. Ties are broken by the order of Parents.
+//
+// Weights are given as in ShortestPath. Every vertex keeps its k best paths
+// from from, computed in topological order by sorting the ones of its
+// parents, so it runs in O(k·E·log(k·D)) time, where D is the largest number
+// of parents of a vertex. It returns an error wrapping ErrNoPath if to is
+// not reachable from from, and a *CycleError if the graph is not acyclic.
+func (d *DAG) KShortestPaths(from *Vertex, to *Vertex, k int, weight func(tail *Vertex, head *Vertex) float64) ([]*Path, error) {
+	if !d.containsVertex(from) {
+		return nil, fmt.Errorf("vertex %s not found in the graph", from.ID)
+	}
+	if !d.containsVertex(to) {
+		return nil, fmt.Errorf("vertex %s not found in the graph", to.ID)
+	}
+	if k <= 0 {
+		return nil, fmt.Errorf("invalid number of paths %d", k)
+	}
+	if weight == nil {
+		weight = d.EdgeWeight
+	}
+
+	sorted, err := d.TopologicalSortStable()
+	if err != nil {
+		return nil, err
+	}
+
+	// pathEntry is one of the best paths to a vertex: its weight, and the
+	// entry of the parent it goes through.
+	type pathEntry struct {
+		weight float64
+		vertex *Vertex
+		parent *pathEntry
+	}
+
+	best := map[*Vertex][]*pathEntry{from: {{vertex: from}}}
+
+	// Only the vertices after from in the topological order, up to to, can
+	// be on a path between them.
+	start := 0
+	for sorted[start] != from {
+		start++
+	}
+	remaining := sorted[start+1:]
+	if from == to {
+		remaining = nil
+	}
+	for _, vertex := range remaining {
+		var candidates []*pathEntry
+		for _, parent := range vertex.Parents.Values() {
+			parent := parent.(*Vertex)
+			for _, entry := range best[parent] {
+				candidates = append(candidates, &pathEntry{entry.weight + weight(parent, vertex), vertex, entry})
+			}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+
+		if k == 1 {
+			// A single path only needs the first lightest candidate, so
+			// there is nothing to sort.
+			lightest := candidates[0]
+			for _, candidate := range candidates[1:] {
+				if candidate.weight < lightest.weight {
+					lightest = candidate
+				}
+			}
+			candidates = []*pathEntry{lightest}
+		} else {
+			sort.SliceStable(candidates, func(i, j int) bool {
+				return candidates[i].weight < candidates[j].weight
+			})
+			if len(candidates) > k {
+				candidates = candidates[:k]
+			}
+		}
+		best[vertex] = candidates
+
+		if vertex == to {
+			break
+		}
+	}
+
+	if len(best[to]) == 0 {
+		return nil, fmt.Errorf("%w from %s to %s", ErrNoPath, from.ID, to.ID)
+	}
+
+	paths := make([]*Path, len(best[to]))
+	for i, entry := range best[to] {
+		path := &Path{Weight: entry.weight}
+		for e := entry; e != nil; e = e.parent {
+			path.Vertices = append(path.Vertices, e.vertex)
+		}
+		for i, j := 0, len(path.Vertices)-1; i < j; i, j = i+1, j-1 {
+			path.Vertices[i], path.Vertices[j] = path.Vertices[j], path.Vertices[i]
+		}
+		paths[i] = path
+	}
+
+	return paths, nil
+}
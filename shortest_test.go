@@ -0,0 +1,160 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+// newRouteDAG return the graph used by the path tests, with the weight of
+// its edges.
+func newRouteDAG(t *testing.T) (*dag.DAG, map[string]*dag.Vertex, func(tail *dag.Vertex, head *dag.Vertex) float64) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"src", "a", "b", "c", "dst", "other"},
+		[][2]string{
+			{"src", "a"}, {"src", "b"}, {"a", "c"}, {"b", "c"},
+			{"a", "dst"}, {"c", "dst"}, {"b", "dst"},
+		},
+	)
+	weights := map[[2]string]float64{
+		{"src", "a"}: 1, {"src", "b"}: 2, {"a", "c"}: 1, {"b", "c"}: -1,
+		{"a", "dst"}: 5, {"c", "dst"}: 2, {"b", "dst"}: 4,
+	}
+	weight := func(tail *dag.Vertex, head *dag.Vertex) float64 {
+		return weights[[2]string{tail.ID, head.ID}]
+	}
+
+	return dag1, vertices, weight
+}
+
+func TestDAG_ShortestPath(t *testing.T) {
+	dag1, vertices, weight := newRouteDAG(t)
+
+	path, total, err := dag1.ShortestPath(vertices["src"], vertices["dst"], weight)
+	if err != nil {
+		t.Fatalf("Can't get shortest path: %s", err)
+	}
+
+	expected := []string{"src", "b", "c", "dst"}
+	if !reflect.DeepEqual(vertexIDs(path), expected) {
+		t.Fatalf("Expected shortest path to be %v but got %v", expected, vertexIDs(path))
+	}
+	if total != 3 {
+		t.Fatalf("Expected shortest path weight to be 3 but got %v", total)
+	}
+
+	// Without weights, the path with the fewest edges wins.
+	path, total, err = dag1.ShortestPath(vertices["src"], vertices["dst"], nil)
+	if err != nil {
+		t.Fatalf("Can't get shortest path: %s", err)
+	}
+	expected = []string{"src", "a", "dst"}
+	if !reflect.DeepEqual(vertexIDs(path), expected) || total != 2 {
+		t.Fatalf("Expected shortest path to be %v with weight 2 but got %v with weight %v", expected, vertexIDs(path), total)
+	}
+
+	path, total, err = dag1.ShortestPath(vertices["a"], vertices["a"], weight)
+	if err != nil {
+		t.Fatalf("Can't get shortest path: %s", err)
+	}
+	if !reflect.DeepEqual(vertexIDs(path), []string{"a"}) || total != 0 {
+		t.Fatalf("Expected shortest path to be [a] with weight 0 but got %v with weight %v", vertexIDs(path), total)
+	}
+}
+
+func TestDAG_ShortestPath_Errors(t *testing.T) {
+	dag1, vertices, weight := newRouteDAG(t)
+
+	_, _, err := dag1.ShortestPath(vertices["dst"], vertices["src"], weight)
+	if !errors.Is(err, dag.ErrNoPath) {
+		t.Fatalf("Expected error to be dag.ErrNoPath but got %v", err)
+	}
+	if err.Error() != "no path from dst to src" {
+		t.Fatalf("Expected error message to be %q but got %q", "no path from dst to src", err.Error())
+	}
+
+	_, _, err = dag1.ShortestPath(vertices["src"], vertices["other"], weight)
+	if !errors.Is(err, dag.ErrNoPath) {
+		t.Fatalf("Expected error to be dag.ErrNoPath but got %v", err)
+	}
+
+	_, _, err = dag1.ShortestPath(vertices["src"], dag.NewVertex("dst", nil), weight)
+	if err == nil || errors.Is(err, dag.ErrNoPath) {
+		t.Fatalf("Vertex doesn't belong to the graph, ShortestPath should fail but got %v", err)
+	}
+
+	_, err = dag1.KShortestPaths(vertices["src"], vertices["dst"], 0, weight)
+	if err == nil {
+		t.Fatalf("Number of paths is invalid, KShortestPaths should fail but it doesn't")
+	}
+}
+
+func TestDAG_KShortestPaths(t *testing.T) {
+	dag1, vertices, weight := newRouteDAG(t)
+
+	paths, err := dag1.KShortestPaths(vertices["src"], vertices["dst"], 4, weight)
+	if err != nil {
+		t.Fatalf("Can't get shortest paths: %s", err)
+	}
+
+	expected := []struct {
+		ids    []string
+		weight float64
+	}{
+		{[]string{"src", "b", "c", "dst"}, 3},
+		{[]string{"src", "a", "c", "dst"}, 4},
+		{[]string{"src", "a", "dst"}, 6},
+		{[]string{"src", "b", "dst"}, 6},
+	}
+	if len(paths) != len(expected) {
+		t.Fatalf("Expected %d paths but got %d", len(expected), len(paths))
+	}
+	for i, path := range paths {
+		if !reflect.DeepEqual(vertexIDs(path.Vertices), expected[i].ids) || path.Weight != expected[i].weight {
+			t.Fatalf("Expected path %d to be %v with weight %v but got %v with weight %v",
+				i, expected[i].ids, expected[i].weight, vertexIDs(path.Vertices), path.Weight)
+		}
+	}
+
+	// There are only 4 paths.
+	paths, err = dag1.KShortestPaths(vertices["src"], vertices["dst"], 10, weight)
+	if err != nil {
+		t.Fatalf("Can't get shortest paths: %s", err)
+	}
+	if len(paths) != 4 {
+		t.Fatalf("Expected 4 paths but got %d", len(paths))
+	}
+}
+
+func TestDAG_KShortestPaths_Cycle(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"a", "b"},
+		[][2]string{{"a", "b"}},
+	)
+	vertices["b"].Children.Add(vertices["a"])
+	vertices["a"].Parents.Add(vertices["b"])
+
+	_, err := dag1.KShortestPaths(vertices["a"], vertices["b"], 2, nil)
+	var cycleErr *dag.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected error to be a *dag.CycleError but got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// svgMargin is the space around the diagram in an SVG document.
+const svgMargin = 10
+
+// WriteSVG writes the graph as an SVG document, drawn with the layered
+// layout computed by Layout with the given options.
+func (d *DAG) WriteSVG(w io.Writer, opts *LayoutOptions) error {
+	layout, err := d.Layout(opts)
+	if err != nil {
+		return err
+	}
+
+	return layout.WriteSVG(w)
+}
+
+// WriteSVG writes the layout as an SVG document, with a labelled box for
+// each vertex and an arrow for each edge.
+func (l *Layout) WriteSVG(w io.Writer) error {
+	b := bufio.NewWriter(w)
+
+	width, height := l.Width+2*svgMargin, l.Height+2*svgMargin
+	fmt.Fprintf(b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%s\" height=\"%s\" viewBox=\"0 0 %s %s\">\n",
+		svgNumber(width), svgNumber(height), svgNumber(width), svgNumber(height))
+	fmt.Fprintf(b, "  <defs>\n")
+	fmt.Fprintf(b, "    <marker id=\"arrow\" viewBox=\"0 0 10 10\" refX=\"10\" refY=\"5\" markerWidth=\"8\" markerHeight=\"8\" orient=\"auto\">\n")
+	fmt.Fprintf(b, "      <path d=\"M 0 0 L 10 5 L 0 10 z\"/>\n")
+	fmt.Fprintf(b, "    </marker>\n")
+	fmt.Fprintf(b, "  </defs>\n")
+	fmt.Fprintf(b, "  <g transform=\"translate(%d %d)\">\n", svgMargin, svgMargin)
+
+	fmt.Fprintf(b, "    <g class=\"edges\" fill=\"none\" stroke=\"black\">\n")
+	for _, edge := range l.Edges {
+		points := make([]string, len(edge.Points))
+		for i, p := range edge.Points {
+			points[i] = svgNumber(p.X) + "," + svgNumber(p.Y)
+		}
+		fmt.Fprintf(b, "      <polyline points=\"%s\" marker-end=\"url(#arrow)\"/>\n", strings.Join(points, " "))
+	}
+	fmt.Fprintf(b, "    </g>\n")
+
+	fmt.Fprintf(b, "    <g class=\"nodes\" font-family=\"sans-serif\" font-size=\"12\" text-anchor=\"middle\">\n")
+	for _, node := range l.Nodes {
+		fmt.Fprintf(b, "      <g class=\"node\" id=\"%s\">\n", xmlEscape(node.ID))
+		fmt.Fprintf(b, "        <rect x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\" rx=\"4\" fill=\"white\" stroke=\"black\"/>\n",
+			svgNumber(node.X-node.Width/2), svgNumber(node.Y-node.Height/2), svgNumber(node.Width), svgNumber(node.Height))
+		fmt.Fprintf(b, "        <text x=\"%s\" y=\"%s\" dominant-baseline=\"central\">%s</text>\n",
+			svgNumber(node.X), svgNumber(node.Y), xmlEscape(node.Label))
+		fmt.Fprintf(b, "      </g>\n")
+	}
+	fmt.Fprintf(b, "    </g>\n")
+
+	fmt.Fprintf(b, "  </g>\n")
+	fmt.Fprintf(b, "</svg>\n")
+
+	return b.Flush()
+}
+
+// svgNumber return a coordinate rounded to two decimals.
+func svgNumber(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
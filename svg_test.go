@@ -0,0 +1,111 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+func TestDAG_WriteSVG(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"a", "b", "c"},
+		[][2]string{{"a", "b"}, {"a", "c"}, {"b", "c"}},
+	)
+	vertices["a"].Value = "<fetch & build>"
+
+	var buf bytes.Buffer
+	err := dag1.WriteSVG(&buf, &dag.LayoutOptions{Label: dag.ValueLabel})
+	if err != nil {
+		t.Fatalf("Can't write SVG: %s", err)
+	}
+
+	counts := make(map[string]int)
+	var texts []string
+	decoder := xml.NewDecoder(&buf)
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Can't parse SVG: %s", err)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			counts[tok.Name.Local]++
+		case xml.CharData:
+			if text := strings.TrimSpace(string(tok)); text != "" {
+				texts = append(texts, text)
+			}
+		}
+	}
+
+	if counts["svg"] != 1 || counts["rect"] != 3 || counts["polyline"] != 3 {
+		t.Fatalf("Expected SVG to have 3 boxes and 3 edges but got %v", counts)
+	}
+	if texts[0] != "<fetch & build>" {
+		t.Fatalf("Expected first label to be %q but got %q", "<fetch & build>", texts[0])
+	}
+}
+
+func TestLayout_WriteSVG(t *testing.T) {
+	layout := &dag.Layout{
+		Width:  40,
+		Height: 20.125,
+		Nodes: []*dag.LayoutNode{
+			{ID: "a", Label: "A", X: 20, Y: 10, Width: 40, Height: 20.125},
+		},
+		Edges: []*dag.LayoutEdge{
+			{Points: []dag.Point{{X: 0, Y: 0}, {X: 1.5, Y: 2.25}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := layout.WriteSVG(&buf)
+	if err != nil {
+		t.Fatalf("Can't write SVG: %s", err)
+	}
+
+	expected := `<svg xmlns="http://www.w3.org/2000/svg" width="60" height="40.13" viewBox="0 0 60 40.13">
+  <defs>
+    <marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto">
+      <path d="M 0 0 L 10 5 L 0 10 z"/>
+    </marker>
+  </defs>
+  <g transform="translate(10 10)">
+    <g class="edges" fill="none" stroke="black">
+      <polyline points="0,0 1.5,2.25" marker-end="url(#arrow)"/>
+    </g>
+    <g class="nodes" font-family="sans-serif" font-size="12" text-anchor="middle">
+      <g class="node" id="a">
+        <rect x="0" y="-0.06" width="40" height="20.13" rx="4" fill="white" stroke="black"/>
+        <text x="20" y="10" dominant-baseline="central">A</text>
+      </g>
+    </g>
+  </g>
+</svg>
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected SVG output to be %q but got %q", expected, buf.String())
+	}
+}
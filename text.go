@@ -0,0 +1,291 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// TextOptions configures the output of RenderText.
+type TextOptions struct {
+	// ASCII draws the graph with ASCII characters only, instead of Unicode
+	// box-drawing characters.
+	ASCII bool
+
+	// Label return the label of a vertex. If nil, vertices are labelled
+	// with their ID. Use ValueLabel to label them with their Value.
+	Label func(v *Vertex) string
+}
+
+// textCharset is the set of characters used to draw a graph as text.
+type textCharset struct {
+	node       rune
+	vertical   rune
+	horizontal rune
+	branch     rune // a lane going on with a line to its right
+	cross      rune // a lane crossed by a line
+	mergeEnd   rune // the last lane joining a line on its left
+	mergeMid   rune // a lane joining a line going on to both sides
+	splitEnd   rune // a lane leaving a line on its left
+	splitMid   rune // a lane leaving a line going on to both sides
+}
+
+var (
+	unicodeCharset = &textCharset{'●', '│', '─', '├', '┼', '┘', '┴', '┐', '┬'}
+	asciiCharset   = &textCharset{'*', '|', '-', '+', '+', '\'', '+', '.', '+'}
+)
+
+// RenderText draws the graph as text, in a layout similar to the one of
+// git log --graph.
+//
+// Vertices are drawn one per row, by topological generation, each one
+// followed by its label. Every edge runs down a lane from its tail to its
+// head. Lanes leaving a vertex split from its lane, and lanes reaching a
+// vertex merge into its lane. Within a generation, vertices are ordered by
+// the mean position of their parents, which reduces edge crossings.
+//
+// If opts is nil the defaults are used.
+func (d *DAG) RenderText(w io.Writer, opts *TextOptions) error {
+	if opts == nil {
+		opts = &TextOptions{}
+	}
+	charset := unicodeCharset
+	if opts.ASCII {
+		charset = asciiCharset
+	}
+	label := opts.Label
+	if label == nil {
+		label = func(v *Vertex) string {
+			return v.ID
+		}
+	}
+
+	order, err := d.textOrder()
+	if err != nil {
+		return err
+	}
+	position := make(map[*Vertex]int, len(order))
+	for i, vertex := range order {
+		position[vertex] = i
+	}
+
+	b := bufio.NewWriter(w)
+
+	// lanes holds the head of the edge running down each lane, or nil for a
+	// free lane.
+	var lanes []*Vertex
+
+	for _, vertex := range order {
+		var targets []int
+		for i, head := range lanes {
+			if head == vertex {
+				targets = append(targets, i)
+			}
+		}
+
+		// Merge every lane reaching the vertex into the leftmost one.
+		var column int
+		switch {
+		case len(targets) == 0:
+			column = freeLane(&lanes, 0)
+		case len(targets) == 1:
+			column = targets[0]
+		default:
+			column = targets[0]
+			last := targets[len(targets)-1]
+			writeTextRow(b, lanes, charset, column, last, func(i int) rune {
+				switch {
+				case i == column:
+					return charset.branch
+				case i == last:
+					return charset.mergeEnd
+				case lanes[i] == vertex:
+					return charset.mergeMid
+				case lanes[i] != nil:
+					return charset.cross
+				}
+				return charset.horizontal
+			})
+			for _, i := range targets[1:] {
+				lanes[i] = nil
+			}
+			lanes = trimLanes(lanes)
+		}
+
+		lanes[column] = vertex
+		row := textLanes(lanes, charset, func(i int) rune {
+			if i == column {
+				return charset.node
+			}
+			return 0
+		})
+		fmt.Fprintf(b, "%s  %s\n", row, strings.ReplaceAll(label(vertex), "\n", " "))
+
+		// Split a lane for every child but the first one, which goes on in
+		// the lane of the vertex. Children are taken in drawing order, so
+		// the nearest lanes are merged first.
+		children := make([]*Vertex, 0, vertex.Children.Size())
+		for _, child := range vertex.Children.Values() {
+			if _, found := position[child.(*Vertex)]; found {
+				children = append(children, child.(*Vertex))
+			}
+		}
+		sort.Slice(children, func(i, j int) bool {
+			return position[children[i]] < position[children[j]]
+		})
+
+		if len(children) == 0 {
+			lanes[column] = nil
+		} else {
+			lanes[column] = children[0]
+		}
+		if len(children) > 1 {
+			split := make(map[int]bool, len(children)-1)
+			last := column
+			for _, child := range children[1:] {
+				i := freeLane(&lanes, column+1)
+				lanes[i] = child
+				split[i] = true
+				last = i
+			}
+			writeTextRow(b, lanes, charset, column, last, func(i int) rune {
+				switch {
+				case i == column:
+					return charset.branch
+				case i == last:
+					return charset.splitEnd
+				case split[i]:
+					return charset.splitMid
+				case lanes[i] != nil:
+					return charset.cross
+				}
+				return charset.horizontal
+			})
+		}
+
+		lanes = trimLanes(lanes)
+	}
+
+	return b.Flush()
+}
+
+// textOrder return the vertices of the graph in the order RenderText draws
+// them: by generation, and within a generation by the mean position of
+// their parents, keeping insertion order for ties.
+func (d *DAG) textOrder() ([]*Vertex, error) {
+	generations, err := d.Generations()
+	if err != nil {
+		return nil, err
+	}
+
+	var order []*Vertex
+	position := make(map[*Vertex]int, d.Order())
+
+	for _, generation := range generations {
+		barycenter := make(map[*Vertex]float64, len(generation))
+		for _, vertex := range generation {
+			parents := vertex.Parents.Values()
+			if len(parents) == 0 {
+				continue
+			}
+			sum := 0
+			for _, parent := range parents {
+				sum += position[parent.(*Vertex)]
+			}
+			barycenter[vertex] = float64(sum) / float64(len(parents))
+		}
+
+		sorted := append([]*Vertex{}, generation...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return barycenter[sorted[i]] < barycenter[sorted[j]]
+		})
+
+		for _, vertex := range sorted {
+			position[vertex] = len(order)
+			order = append(order, vertex)
+		}
+	}
+
+	return order, nil
+}
+
+// freeLane return the first free lane from the given one, adding a new lane
+// if there is none.
+func freeLane(lanes *[]*Vertex, from int) int {
+	for i := from; i < len(*lanes); i++ {
+		if (*lanes)[i] == nil {
+			return i
+		}
+	}
+	*lanes = append(*lanes, nil)
+
+	return len(*lanes) - 1
+}
+
+// trimLanes return the lanes without the free ones at the end.
+func trimLanes(lanes []*Vertex) []*Vertex {
+	for len(lanes) > 0 && lanes[len(lanes)-1] == nil {
+		lanes = lanes[:len(lanes)-1]
+	}
+
+	return lanes
+}
+
+// writeTextRow writes a row joining lanes with a horizontal line from the
+// lane at from to the one at to. Lanes on the line are drawn as returned by
+// cell.
+func writeTextRow(b *bufio.Writer, lanes []*Vertex, charset *textCharset, from int, to int, cell func(i int) rune) {
+	row := textLanes(lanes, charset, func(i int) rune {
+		if i >= from && i <= to {
+			return cell(i)
+		}
+		return 0
+	})
+	runes := []rune(row)
+	for i := 2*from + 1; i < 2*to; i += 2 {
+		runes[i] = charset.horizontal
+	}
+
+	fmt.Fprintf(b, "%s\n", strings.TrimRight(string(runes), " "))
+}
+
+// textLanes return a row drawing the lanes, each one as returned by cell,
+// or as a vertical line or a blank if cell return zero.
+func textLanes(lanes []*Vertex, charset *textCharset, cell func(i int) rune) string {
+	runes := make([]rune, 0, 2*len(lanes))
+	for i, head := range lanes {
+		if i > 0 {
+			runes = append(runes, ' ')
+		}
+		r := cell(i)
+		switch {
+		case r != 0:
+		case head != nil:
+			r = charset.vertical
+		default:
+			r = ' '
+		}
+		runes = append(runes, r)
+	}
+
+	return string(runes)
+}
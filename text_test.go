@@ -0,0 +1,116 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+func TestDAG_RenderText(t *testing.T) {
+	dag1, _ := newTestDAG(t,
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}},
+	)
+
+	var buf bytes.Buffer
+	err := dag1.RenderText(&buf, nil)
+	if err != nil {
+		t.Fatalf("Can't render DAG: %s", err)
+	}
+
+	expected := `●  a
+├─┐
+● │  b
+│ ●  c
+├─┘
+●  d
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected text output to be %q but got %q", expected, buf.String())
+	}
+}
+
+func TestDAG_RenderText_ASCII(t *testing.T) {
+	dag1, _ := newTestDAG(t,
+		[]string{"a", "b", "c", "d", "e", "f", "g", "h"},
+		[][2]string{
+			{"a", "b"}, {"a", "c"}, {"a", "e"}, {"a", "g"},
+			{"b", "d"}, {"b", "f"}, {"c", "d"}, {"e", "f"}, {"g", "d"},
+		},
+	)
+
+	var buf bytes.Buffer
+	err := dag1.RenderText(&buf, &dag.TextOptions{
+		ASCII: true,
+		Label: func(v *dag.Vertex) string {
+			return "step " + strings.ToUpper(v.ID)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Can't render DAG: %s", err)
+	}
+
+	expected := `*  step A
++-+-+-.
+| | | | *  step H
+* | | |  step B
++-+-+-+-.
+| * | | |  step C
+| | * | |  step E
+| | | * |  step G
++-+-' | |
+* |   | |  step F
+  +---+-'
+  *  step D
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected text output to be %q but got %q", expected, buf.String())
+	}
+}
+
+func TestDAG_RenderText_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	err := dag.NewDAG().RenderText(&buf, nil)
+	if err != nil {
+		t.Fatalf("Can't render DAG: %s", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Fatalf("Expected text output to be empty but got %q", buf.String())
+	}
+}
+
+func TestDAG_RenderText_Cycle(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"a", "b"},
+		[][2]string{{"a", "b"}},
+	)
+	vertices["b"].Children.Add(vertices["a"])
+	vertices["a"].Parents.Add(vertices["b"])
+
+	var buf bytes.Buffer
+	err := dag1.RenderText(&buf, nil)
+	var cycleErr *dag.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected error to be a *dag.CycleError but got %v", err)
+	}
+}
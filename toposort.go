@@ -0,0 +1,178 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag
+
+import (
+	"container/heap"
+)
+
+// TopologicalSort return the vertices of the graph in a topological order, a
+// sequence where every edge goes from an earlier vertex to a later one.
+//
+// The order is computed with a depth first search started from each vertex
+// in insertion order. It returns a *CycleError if the graph is not acyclic,
+// which can only happen when Parents or Children were modified by hand.
+func (d *DAG) TopologicalSort() ([]*Vertex, error) {
+	vertices := d.Vertices()
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[*Vertex]int, len(vertices))
+	for _, vertex := range vertices {
+		state[vertex] = unvisited
+	}
+
+	// sorted is filled in post order and reversed at the end.
+	sorted := make([]*Vertex, 0, len(vertices))
+	var stack []*Vertex
+
+	var visit func(vertex *Vertex) error
+	visit = func(vertex *Vertex) error {
+		state[vertex] = visiting
+		stack = append(stack, vertex)
+
+		for _, child := range vertex.Children.Values() {
+			child := child.(*Vertex)
+			childState, found := state[child]
+			if !found {
+				continue
+			}
+			switch childState {
+			case visiting:
+				return newStackCycleError(stack, child)
+			case unvisited:
+				if err := visit(child); err != nil {
+					return err
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[vertex] = visited
+		sorted = append(sorted, vertex)
+
+		return nil
+	}
+
+	for _, vertex := range vertices {
+		if state[vertex] != unvisited {
+			continue
+		}
+		if err := visit(vertex); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+		sorted[i], sorted[j] = sorted[j], sorted[i]
+	}
+
+	return sorted, nil
+}
+
+// TopologicalSortStable return the vertices of the graph in a topological
+// order, breaking ties by insertion order.
+//
+// It uses Kahn's algorithm and, among all the vertices whose parents are
+// already sorted, always picks the one added first to the graph. So the
+// result only depends on the graph and the order in which its vertices were
+// added. It returns a *CycleError if the graph is not acyclic.
+func (d *DAG) TopologicalSortStable() ([]*Vertex, error) {
+	vertices := d.Vertices()
+
+	index := make(map[*Vertex]int, len(vertices))
+	for i, vertex := range vertices {
+		index[vertex] = i
+	}
+
+	inDegree := make([]int, len(vertices))
+	for _, vertex := range vertices {
+		for _, child := range vertex.Children.Values() {
+			if i, found := index[child.(*Vertex)]; found {
+				inDegree[i]++
+			}
+		}
+	}
+
+	ready := &indexHeap{}
+	for i := range vertices {
+		if inDegree[i] == 0 {
+			heap.Push(ready, i)
+		}
+	}
+
+	sorted := make([]*Vertex, 0, len(vertices))
+	for ready.Len() > 0 {
+		vertex := vertices[heap.Pop(ready).(int)]
+		sorted = append(sorted, vertex)
+
+		for _, child := range vertex.Children.Values() {
+			i, found := index[child.(*Vertex)]
+			if !found {
+				continue
+			}
+			inDegree[i]--
+			if inDegree[i] == 0 {
+				heap.Push(ready, i)
+			}
+		}
+	}
+
+	if len(sorted) != len(vertices) {
+		// Some vertices never got all their parents sorted, so at least one
+		// cycle goes through them. Let the depth first search describe it.
+		_, err := d.TopologicalSort()
+		return nil, err
+	}
+
+	return sorted, nil
+}
+
+// newStackCycleError returns a CycleError for the cycle that closes when the
+// last vertex in a depth first search stack has an edge to vertex.
+func newStackCycleError(stack []*Vertex, vertex *Vertex) *CycleError {
+	start := len(stack) - 1
+	for start > 0 && stack[start] != vertex {
+		start--
+	}
+
+	path := make([]*Vertex, 0, len(stack)-start+1)
+	path = append(path, stack[start:]...)
+	path = append(path, vertex)
+
+	return &CycleError{Path: path}
+}
+
+// indexHeap is a min-heap of vertex insertion indexes.
+type indexHeap []int
+
+func (h indexHeap) Len() int            { return len(h) }
+func (h indexHeap) Less(i, j int) bool  { return h[i] < h[j] }
+func (h indexHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *indexHeap) Push(x interface{}) { *h = append(*h, x.(int)) }
+func (h *indexHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+
+	return x
+}
@@ -0,0 +1,116 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+// checkTopologicalOrder fails the test if sorted isn't a topological order
+// of every vertex in the graph.
+func checkTopologicalOrder(t *testing.T, dag1 *dag.DAG, sorted []*dag.Vertex) {
+	if len(sorted) != dag1.Order() {
+		t.Fatalf("Expected %d sorted vertices but got %d", dag1.Order(), len(sorted))
+	}
+
+	position := make(map[*dag.Vertex]int, len(sorted))
+	for i, vertex := range sorted {
+		position[vertex] = i
+	}
+
+	for _, vertex := range sorted {
+		for _, child := range vertex.Children.Values() {
+			child := child.(*dag.Vertex)
+			if position[vertex] >= position[child] {
+				t.Fatalf("Edge (%s,%s) goes backwards in %v", vertex.ID, child.ID, vertexIDs(sorted))
+			}
+		}
+	}
+}
+
+func TestDAG_TopologicalSort(t *testing.T) {
+	dag1, _ := newTestDAG(t,
+		[]string{"4", "3", "2", "1", "5"},
+		[][2]string{{"1", "2"}, {"2", "3"}, {"2", "4"}, {"4", "3"}, {"5", "4"}},
+	)
+
+	sorted, err := dag1.TopologicalSort()
+	if err != nil {
+		t.Fatalf("Can't sort DAG: %s", err)
+	}
+	checkTopologicalOrder(t, dag1, sorted)
+}
+
+func TestDAG_TopologicalSort_Empty(t *testing.T) {
+	dag1 := dag.NewDAG()
+
+	sorted, err := dag1.TopologicalSort()
+	if err != nil {
+		t.Fatalf("Can't sort DAG: %s", err)
+	}
+	if len(sorted) != 0 {
+		t.Fatalf("Expected 0 sorted vertices but got %d", len(sorted))
+	}
+}
+
+func TestDAG_TopologicalSortStable(t *testing.T) {
+	dag1, _ := newTestDAG(t,
+		[]string{"e", "d", "c", "b", "a"},
+		[][2]string{{"a", "b"}, {"c", "b"}, {"b", "d"}, {"e", "d"}},
+	)
+
+	sorted, err := dag1.TopologicalSortStable()
+	if err != nil {
+		t.Fatalf("Can't sort DAG: %s", err)
+	}
+	checkTopologicalOrder(t, dag1, sorted)
+
+	expected := []string{"e", "c", "a", "b", "d"}
+	if !reflect.DeepEqual(vertexIDs(sorted), expected) {
+		t.Fatalf("Expected sorted vertices to be %v but got %v", expected, vertexIDs(sorted))
+	}
+}
+
+func TestDAG_TopologicalSort_Cycle(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"a", "b", "c"},
+		[][2]string{{"a", "b"}, {"b", "c"}},
+	)
+
+	// Bypass AddEdge to corrupt the graph.
+	vertices["c"].Children.Add(vertices["a"])
+	vertices["a"].Parents.Add(vertices["c"])
+
+	_, err := dag1.TopologicalSort()
+	cycleErr, ok := err.(*dag.CycleError)
+	if !ok {
+		t.Fatalf("Expected error to be a *dag.CycleError but got %v", err)
+	}
+	expected := []string{"a", "b", "c", "a"}
+	if !reflect.DeepEqual(vertexIDs(cycleErr.Path), expected) {
+		t.Fatalf("Expected cycle path to be %v but got %v", expected, vertexIDs(cycleErr.Path))
+	}
+
+	_, err = dag1.TopologicalSortStable()
+	if _, ok := err.(*dag.CycleError); !ok {
+		t.Fatalf("Expected error to be a *dag.CycleError but got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag
+
+// TransitiveReduction removes from the graph every edge implied by the
+// others, that is every edge from a vertex to another one also reachable
+// through a longer path, such as a -> c when a -> b -> c exists.
+//
+// The result is the only graph with the fewest edges and the same
+// reachability. Vertices and the order of the remaining children are left
+// untouched. The label, weight and attributes of the removed edges are not
+// moved to the remaining ones, so the removed edges are returned, by tail
+// vertex insertion order and then by child order.
+//
+// It runs in O(V·E) time and returns a *CycleError if the graph is not
+// acyclic.
+func (d *DAG) TransitiveReduction() ([]*Edge, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := d.TopologicalSort(); err != nil {
+		return nil, err
+	}
+
+	var removed []*Edge
+	for _, vertex := range d.Vertices() {
+		// Mark every vertex reachable from a child through at least one
+		// more edge. The edges to marked children are redundant.
+		marked := make(map[*Vertex]bool)
+		var stack []*Vertex
+		for _, child := range vertex.Children.Values() {
+			for _, grandchild := range child.(*Vertex).Children.Values() {
+				grandchild := grandchild.(*Vertex)
+				if !marked[grandchild] {
+					marked[grandchild] = true
+					stack = append(stack, grandchild)
+				}
+			}
+		}
+		for len(stack) > 0 {
+			v := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			for _, child := range v.Children.Values() {
+				child := child.(*Vertex)
+				if !marked[child] {
+					marked[child] = true
+					stack = append(stack, child)
+				}
+			}
+		}
+
+		for _, child := range vertex.Children.Values() {
+			child := child.(*Vertex)
+			if marked[child] {
+				removed = append(removed, d.edge(vertex, child))
+				vertex.Children.Remove(child)
+				child.Parents.Remove(vertex)
+				delete(d.edges, edgeKey{vertex, child})
+				if d.reach != nil {
+					d.reach.removeChild(vertex, child)
+				}
+			}
+		}
+	}
+
+	// Removing implied edges doesn't change reachability, so the labels of
+	// the reachability index stay valid, with just their children updated.
+
+	return removed, nil
+}
+
+// TransitiveClosure adds to the graph an edge from every vertex to each of
+// its descendants, so that every path is also an edge.
+//
+// Vertices are left untouched, and new children are added after the
+// existing ones in breadth first order. New edges get the defaults of
+// AddEdge, no label, a weight of 1 and no attributes, whatever the data of
+// the edges along the paths they join. It runs in O(V·E) time and returns a
+// *CycleError if the graph is not acyclic.
+func (d *DAG) TransitiveClosure() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := d.TopologicalSort(); err != nil {
+		return err
+	}
+
+	// Compute every closure before adding edges, so that each vertex gets
+	// its descendants in breadth first order over the original graph.
+	vertices := d.Vertices()
+	descendants := make([][]*Vertex, len(vertices))
+	for i, vertex := range vertices {
+		descendants[i], _ = d.Descendants(vertex, nil)
+	}
+
+	for i, vertex := range vertices {
+		for _, descendant := range descendants[i] {
+			if !vertex.Children.Contains(descendant) {
+				vertex.Children.Add(descendant)
+				descendant.Parents.Add(vertex)
+				d.edges[edgeKey{vertex, descendant}] = newEdge(vertex, descendant)
+				if d.reach != nil {
+					d.reach.addChild(vertex, descendant)
+				}
+			}
+		}
+	}
+
+	// Adding implied edges doesn't change reachability either.
+
+	return nil
+}
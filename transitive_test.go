@@ -0,0 +1,152 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+func TestDAG_TransitiveReduction(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{
+			{"a", "c"}, {"a", "b"}, {"b", "c"}, {"a", "d"},
+			{"c", "d"}, {"b", "d"}, {"d", "e"}, {"a", "e"},
+		},
+	)
+	vertices["a"].Value = "keep"
+
+	ok, err := dag1.IsReachable(vertices["a"], vertices["e"])
+	if err != nil || !ok {
+		t.Fatalf("Expected e to be reachable from a but got %v, %v", ok, err)
+	}
+
+	edge, _ := dag1.GetEdge(vertices["a"], vertices["c"])
+	edge.Label = "shortcut"
+
+	removed, err := dag1.TransitiveReduction()
+	if err != nil {
+		t.Fatalf("Can't reduce DAG: %s", err)
+	}
+
+	// The removed edges are returned with their data.
+	var removedIDs []string
+	for _, edge := range removed {
+		removedIDs = append(removedIDs, edge.Tail.ID+"->"+edge.Head.ID)
+	}
+	expectedRemoved := []string{"a->c", "a->d", "a->e", "b->d"}
+	if !reflect.DeepEqual(removedIDs, expectedRemoved) {
+		t.Fatalf("Expected removed edges to be %v but got %v", expectedRemoved, removedIDs)
+	}
+	if removed[0].Label != "shortcut" {
+		t.Fatalf("Expected removed edge a->c label to be %q but got %q", "shortcut", removed[0].Label)
+	}
+
+	expected := []string{"a->b", "b->c", "c->d", "d->e"}
+	if !reflect.DeepEqual(edgeIDs(dag1), expected) {
+		t.Fatalf("Expected edges to be %v but got %v", expected, edgeIDs(dag1))
+	}
+	for id, vertex := range vertices {
+		v, _ := dag1.GetVertex(id)
+		if v != vertex {
+			t.Fatalf("Expected vertex %s to be kept", id)
+		}
+	}
+	if vertices["a"].Value != "keep" {
+		t.Fatalf("Expected vertex a value to be kept but got %v", vertices["a"].Value)
+	}
+	if vertices["e"].Parents.Size() != 1 {
+		t.Fatalf("Expected vertex e to have 1 parent but got %d", vertices["e"].Parents.Size())
+	}
+	if err := dag1.Integrity(); err != nil {
+		t.Fatalf("Reduced DAG is inconsistent: %s", err)
+	}
+	checkReachability(t, dag1)
+}
+
+func TestDAG_TransitiveClosure(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"a", "d"}, {"c", "e"}},
+	)
+
+	ok, err := dag1.IsReachable(vertices["b"], vertices["d"])
+	if err != nil || ok {
+		t.Fatalf("Expected d not to be reachable from b but got %v, %v", ok, err)
+	}
+
+	edge, _ := dag1.GetEdge(vertices["a"], vertices["b"])
+	edge.Weight = 5
+
+	err = dag1.TransitiveClosure()
+	if err != nil {
+		t.Fatalf("Can't close DAG: %s", err)
+	}
+
+	// New edges get the defaults.
+	if weight := dag1.EdgeWeight(vertices["a"], vertices["c"]); weight != 1 {
+		t.Fatalf("Expected edge a->c weight to be 1 but got %v", weight)
+	}
+
+	expected := []string{
+		"a->b", "a->d", "a->c", "a->e",
+		"b->c", "b->e",
+		"c->e",
+	}
+	if !reflect.DeepEqual(edgeIDs(dag1), expected) {
+		t.Fatalf("Expected edges to be %v but got %v", expected, edgeIDs(dag1))
+	}
+	if err := dag1.Integrity(); err != nil {
+		t.Fatalf("Closed DAG is inconsistent: %s", err)
+	}
+	checkReachability(t, dag1)
+
+	// Reducing the closure gives back the original graph.
+	_, err = dag1.TransitiveReduction()
+	if err != nil {
+		t.Fatalf("Can't reduce DAG: %s", err)
+	}
+	expected = []string{"a->b", "a->d", "b->c", "c->e"}
+	if !reflect.DeepEqual(edgeIDs(dag1), expected) {
+		t.Fatalf("Expected edges to be %v but got %v", expected, edgeIDs(dag1))
+	}
+}
+
+func TestDAG_Transitive_Cycle(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"a", "b"},
+		[][2]string{{"a", "b"}},
+	)
+	vertices["b"].Children.Add(vertices["a"])
+	vertices["a"].Parents.Add(vertices["b"])
+
+	var cycleErr *dag.CycleError
+
+	_, err := dag1.TransitiveReduction()
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected error to be a *dag.CycleError but got %v", err)
+	}
+
+	err = dag1.TransitiveClosure()
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected error to be a *dag.CycleError but got %v", err)
+	}
+}
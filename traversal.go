@@ -0,0 +1,95 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag
+
+import (
+	"fmt"
+)
+
+// TraversalOptions configures the traversal of Ancestors and Descendants.
+type TraversalOptions struct {
+	// MaxDepth is the maximum number of edges between the start vertex and
+	// the vertices returned. Zero means no limit.
+	MaxDepth int
+
+	// Filter return whether a vertex, found at the given depth, belongs to
+	// the result. Vertices left out are still traversed, so their own
+	// ancestors or descendants may be returned. If nil, every vertex is
+	// returned.
+	Filter func(v *Vertex, depth int) bool
+}
+
+// Ancestors return the vertices from which there is a path to the given
+// vertex, that is its parents, their parents and so on.
+//
+// Vertices are returned in breadth first order, each one once at its
+// shortest distance from the given vertex, and in Parents order within the
+// same distance. If opts is nil, every ancestor is returned.
+func (d *DAG) Ancestors(vertex *Vertex, opts *TraversalOptions) ([]*Vertex, error) {
+	return d.traverse(vertex, opts, func(v *Vertex) []interface{} {
+		return v.Parents.Values()
+	})
+}
+
+// Descendants return the vertices to which there is a path from the given
+// vertex, that is its children, their children and so on.
+//
+// Vertices are returned in breadth first order, each one once at its
+// shortest distance from the given vertex, and in Children order within the
+// same distance. If opts is nil, every descendant is returned.
+func (d *DAG) Descendants(vertex *Vertex, opts *TraversalOptions) ([]*Vertex, error) {
+	return d.traverse(vertex, opts, func(v *Vertex) []interface{} {
+		return v.Children.Values()
+	})
+}
+
+// traverse return the vertices found by a breadth first search from vertex
+// following the neighbours returned by next.
+func (d *DAG) traverse(vertex *Vertex, opts *TraversalOptions, next func(*Vertex) []interface{}) ([]*Vertex, error) {
+	if !d.containsVertex(vertex) {
+		return nil, fmt.Errorf("vertex %s not found in the graph", vertex.ID)
+	}
+	if opts == nil {
+		opts = &TraversalOptions{}
+	}
+
+	var result []*Vertex
+	visited := map[*Vertex]bool{vertex: true}
+	level := []*Vertex{vertex}
+
+	for depth := 1; len(level) > 0 && (opts.MaxDepth <= 0 || depth <= opts.MaxDepth); depth++ {
+		var nextLevel []*Vertex
+		for _, v := range level {
+			for _, neighbour := range next(v) {
+				neighbour := neighbour.(*Vertex)
+				if visited[neighbour] {
+					continue
+				}
+				visited[neighbour] = true
+				nextLevel = append(nextLevel, neighbour)
+
+				if opts.Filter == nil || opts.Filter(neighbour, depth) {
+					result = append(result, neighbour)
+				}
+			}
+		}
+		level = nextLevel
+	}
+
+	return result, nil
+}
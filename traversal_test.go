@@ -0,0 +1,130 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+// newTraversalDAG return the graph used by the traversal tests:
+//
+//	raw -> clean -> orders -> report
+//	raw -> users -> report
+//	clean -> audit
+//	users -> tmp_users -> export
+func newTraversalDAG(t *testing.T) (*dag.DAG, map[string]*dag.Vertex) {
+	return newTestDAG(t,
+		[]string{"raw", "clean", "users", "orders", "audit", "tmp_users", "report", "export"},
+		[][2]string{
+			{"raw", "clean"}, {"raw", "users"},
+			{"clean", "orders"}, {"clean", "audit"},
+			{"users", "report"}, {"users", "tmp_users"},
+			{"orders", "report"},
+			{"tmp_users", "export"},
+		},
+	)
+}
+
+func TestDAG_Descendants(t *testing.T) {
+	dag1, vertices := newTraversalDAG(t)
+
+	tests := []struct {
+		vertex   string
+		opts     *dag.TraversalOptions
+		expected []string
+	}{
+		{"raw", nil, []string{"clean", "users", "orders", "audit", "report", "tmp_users", "export"}},
+		{"raw", &dag.TraversalOptions{MaxDepth: 1}, []string{"clean", "users"}},
+		{"raw", &dag.TraversalOptions{MaxDepth: 2}, []string{"clean", "users", "orders", "audit", "report", "tmp_users"}},
+		{"report", nil, []string{}},
+		{
+			"raw",
+			&dag.TraversalOptions{Filter: func(v *dag.Vertex, depth int) bool {
+				return !strings.HasPrefix(v.ID, "tmp_")
+			}},
+			[]string{"clean", "users", "orders", "audit", "report", "export"},
+		},
+		{
+			"raw",
+			&dag.TraversalOptions{Filter: func(v *dag.Vertex, depth int) bool {
+				return depth == 2
+			}},
+			[]string{"orders", "audit", "report", "tmp_users"},
+		},
+	}
+
+	for _, test := range tests {
+		descendants, err := dag1.Descendants(vertices[test.vertex], test.opts)
+		if err != nil {
+			t.Fatalf("Can't get descendants: %s", err)
+		}
+		if !reflect.DeepEqual(vertexIDs(descendants), test.expected) {
+			t.Fatalf("Expected %s descendants to be %v but got %v", test.vertex, test.expected, vertexIDs(descendants))
+		}
+	}
+}
+
+func TestDAG_Ancestors(t *testing.T) {
+	dag1, vertices := newTraversalDAG(t)
+
+	tests := []struct {
+		vertex   string
+		opts     *dag.TraversalOptions
+		expected []string
+	}{
+		{"report", nil, []string{"users", "orders", "raw", "clean"}},
+		{"report", &dag.TraversalOptions{MaxDepth: 1}, []string{"users", "orders"}},
+		{"export", nil, []string{"tmp_users", "users", "raw"}},
+		{"raw", nil, []string{}},
+		{
+			"export",
+			&dag.TraversalOptions{Filter: func(v *dag.Vertex, depth int) bool {
+				return v.ID != "users"
+			}},
+			[]string{"tmp_users", "raw"},
+		},
+	}
+
+	for _, test := range tests {
+		ancestors, err := dag1.Ancestors(vertices[test.vertex], test.opts)
+		if err != nil {
+			t.Fatalf("Can't get ancestors: %s", err)
+		}
+		if !reflect.DeepEqual(vertexIDs(ancestors), test.expected) {
+			t.Fatalf("Expected %s ancestors to be %v but got %v", test.vertex, test.expected, vertexIDs(ancestors))
+		}
+	}
+}
+
+func TestDAG_Ancestors_VertexNotFound(t *testing.T) {
+	dag1, _ := newTraversalDAG(t)
+	outsider := dag.NewVertex("raw", nil)
+
+	_, err := dag1.Ancestors(outsider, nil)
+	if err == nil {
+		t.Fatalf("Vertex doesn't belong to the graph, Ancestors should fail but it doesn't")
+	}
+
+	_, err = dag1.Descendants(outsider, nil)
+	if err == nil {
+		t.Fatalf("Vertex doesn't belong to the graph, Descendants should fail but it doesn't")
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package typed
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/goombaio/dag"
+)
+
+// DAG type implements a type-safe Directed Acyclic Graph data structure.
+type DAG[K comparable, V any] struct {
+	mu       sync.Mutex
+	dag      *dag.DAG
+	vertices map[K]*Vertex[K, V]
+}
+
+// NewDAG creates a new type-safe Directed Acyclic Graph or DAG.
+func NewDAG[K comparable, V any]() *DAG[K, V] {
+	d := &DAG[K, V]{
+		dag:      dag.NewDAG(),
+		vertices: make(map[K]*Vertex[K, V]),
+	}
+
+	return d
+}
+
+// AddVertex adds a vertex to the graph.
+//
+// It fails if the graph already has a vertex with the same ID, or with an ID
+// printed the same way by fmt.Sprint, as they would share their untyped ID.
+func (d *DAG[K, V]) AddVertex(v *Vertex[K, V]) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, found := d.vertices[v.ID]; found {
+		return fmt.Errorf("vertex %v already exists in the graph", v.ID)
+	}
+	if existing, err := d.dag.GetVertex(v.vertex.ID); err == nil {
+		return fmt.Errorf("vertex %v collides with vertex %v", v.ID, typedVertex[K, V](existing).ID)
+	}
+
+	err := d.dag.AddVertex(v.vertex)
+	if err != nil {
+		return err
+	}
+	d.vertices[v.ID] = v
+
+	return nil
+}
+
+// DeleteVertex deletes a vertex and all the edges referencing it from the
+// graph.
+func (d *DAG[K, V]) DeleteVertex(v *Vertex[K, V]) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	err := d.dag.DeleteVertex(v.vertex)
+	if err != nil {
+		return err
+	}
+	delete(d.vertices, v.ID)
+
+	return nil
+}
+
+// AddEdge adds a directed edge between two existing vertices to the graph.
+//
+// It returns a *CycleError if the new edge would close a directed cycle.
+func (d *DAG[K, V]) AddEdge(tailVertex *Vertex[K, V], headVertex *Vertex[K, V]) error {
+	return typedError[K, V](d.dag.AddEdge(tailVertex.vertex, headVertex.vertex))
+}
+
+// DeleteEdge deletes a directed edge between two existing vertices from the
+// graph.
+func (d *DAG[K, V]) DeleteEdge(tailVertex *Vertex[K, V], headVertex *Vertex[K, V]) error {
+	return d.dag.DeleteEdge(tailVertex.vertex, headVertex.vertex)
+}
+
+// GetVertex return a vertex from the graph given a vertex ID.
+func (d *DAG[K, V]) GetVertex(id K) (*Vertex[K, V], error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	v, found := d.vertices[id]
+	if !found {
+		return nil, fmt.Errorf("vertex %v not found in the graph", id)
+	}
+
+	return v, nil
+}
+
+// Untyped return a copy of the graph as an untyped one, to use the parts of
+// package dag with no typed counterpart, such as the exporters or the
+// executor.
+//
+// Its vertices hold the typed values as Value, and VertexOf maps them back to
+// typed vertices. Changes to the copy are not seen by the typed graph.
+func (d *DAG[K, V]) Untyped() *dag.DAG {
+	result := dag.NewDAG()
+
+	vertices := make(map[*dag.Vertex]*dag.Vertex, d.dag.Order())
+	for _, vertex := range d.dag.Vertices() {
+		v := dag.NewVertex(vertex.ID, vertex.Value)
+		for key, value := range vertex.Metadata {
+			v.Metadata[key] = value
+		}
+
+		// Typed vertices have no labels, so AddVertex can't fail.
+		_ = result.AddVertex(v)
+		vertices[vertex] = v
+	}
+
+	// Edges come in the order of Children, which the copy keeps. They form
+	// an acyclic graph, so AddEdge can't fail.
+	for _, edge := range d.dag.Edges() {
+		_ = result.AddEdge(vertices[edge.Tail], vertices[edge.Head])
+	}
+
+	return result
+}
+
+// Order return the number of vertices in the graph.
+func (d *DAG[K, V]) Order() int {
+	return d.dag.Order()
+}
+
+// Size return the number of edges in the graph.
+func (d *DAG[K, V]) Size() int {
+	return d.dag.Size()
+}
+
+// Vertices return the vertices of the graph in insertion order.
+func (d *DAG[K, V]) Vertices() []*Vertex[K, V] {
+	return fromVertices[K, V](d.dag.Vertices())
+}
+
+// Range calls fn for each vertex of the graph in insertion order. If fn
+// returns false, Range stops the iteration.
+func (d *DAG[K, V]) Range(fn func(v *Vertex[K, V]) bool) {
+	for _, vertex := range d.dag.Vertices() {
+		if !fn(typedVertex[K, V](vertex)) {
+			return
+		}
+	}
+}
+
+// SinkVertices return vertices with no children defined by the graph edges.
+func (d *DAG[K, V]) SinkVertices() []*Vertex[K, V] {
+	return fromVertices[K, V](d.dag.SinkVertices())
+}
+
+// SourceVertices return vertices with no parent defined by the graph edges.
+func (d *DAG[K, V]) SourceVertices() []*Vertex[K, V] {
+	return fromVertices[K, V](d.dag.SourceVertices())
+}
+
+// Successors return vertices that are children of a given vertex.
+func (d *DAG[K, V]) Successors(v *Vertex[K, V]) ([]*Vertex[K, V], error) {
+	successors, err := d.dag.Successors(v.vertex)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromVertices[K, V](successors), nil
+}
+
+// Predecessors return vertices that are parent of a given vertex.
+func (d *DAG[K, V]) Predecessors(v *Vertex[K, V]) ([]*Vertex[K, V], error) {
+	predecessors, err := d.dag.Predecessors(v.vertex)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromVertices[K, V](predecessors), nil
+}
+
+// TopologicalSort return the vertices of the graph in a topological order.
+//
+// See dag.DAG.TopologicalSort for details.
+func (d *DAG[K, V]) TopologicalSort() ([]*Vertex[K, V], error) {
+	sorted, err := d.dag.TopologicalSort()
+	if err != nil {
+		return nil, typedError[K, V](err)
+	}
+
+	return fromVertices[K, V](sorted), nil
+}
+
+// TopologicalSortStable return the vertices of the graph in a topological
+// order, breaking ties by insertion order.
+//
+// See dag.DAG.TopologicalSortStable for details.
+func (d *DAG[K, V]) TopologicalSortStable() ([]*Vertex[K, V], error) {
+	sorted, err := d.dag.TopologicalSortStable()
+	if err != nil {
+		return nil, typedError[K, V](err)
+	}
+
+	return fromVertices[K, V](sorted), nil
+}
+
+// Generations return the vertices of the graph grouped in topological
+// generations.
+//
+// See dag.DAG.Generations for details.
+func (d *DAG[K, V]) Generations() ([][]*Vertex[K, V], error) {
+	generations, err := d.dag.Generations()
+	if err != nil {
+		return nil, typedError[K, V](err)
+	}
+
+	result := make([][]*Vertex[K, V], len(generations))
+	for i, generation := range generations {
+		result[i] = fromVertices[K, V](generation)
+	}
+
+	return result, nil
+}
+
+// String implements stringer interface.
+//
+// Prints an string representation of this instance.
+func (d *DAG[K, V]) String() string {
+	result := fmt.Sprintf("DAG Vertices: %d - Edges: %d\n", d.Order(), d.Size())
+	result += fmt.Sprintf("Vertices:\n")
+	for _, vertex := range d.Vertices() {
+		result += fmt.Sprintf("%s", vertex)
+	}
+
+	return result
+}
+
+// CycleError is returned when an operation would make the graph cyclic.
+//
+// Path holds the vertices of the offending cycle in edge order, starting and
+// ending at the same vertex.
+type CycleError[K comparable, V any] struct {
+	Path []*Vertex[K, V]
+}
+
+// Error implements the error interface.
+func (e *CycleError[K, V]) Error() string {
+	ids := make([]string, len(e.Path))
+	for i, vertex := range e.Path {
+		ids[i] = fmt.Sprint(vertex.ID)
+	}
+
+	return "cycle detected: " + strings.Join(ids, " -> ")
+}
+
+// typedError converts a *dag.CycleError into a typed *CycleError, and
+// returns any other error unchanged.
+func typedError[K comparable, V any](err error) error {
+	cycleErr, ok := err.(*dag.CycleError)
+	if !ok {
+		return err
+	}
+
+	return &CycleError[K, V]{Path: fromVertices[K, V](cycleErr.Path)}
+}
+
+// fromVertices return the typed vertices wrapping a list of untyped ones.
+func fromVertices[K comparable, V any](vertices []*dag.Vertex) []*Vertex[K, V] {
+	result := make([]*Vertex[K, V], len(vertices))
+	for i, vertex := range vertices {
+		result[i] = typedVertex[K, V](vertex)
+	}
+
+	return result
+}
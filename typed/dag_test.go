@@ -0,0 +1,424 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package typed_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goombaio/dag"
+	"github.com/goombaio/dag/typed"
+)
+
+type job struct {
+	name string
+}
+
+func TestDAG(t *testing.T) {
+	d := typed.NewDAG[string, job]()
+
+	if d.Order() != 0 {
+		t.Fatalf("DAG number of vertices expected to be 0 but got %d", d.Order())
+	}
+}
+
+func TestDAG_AddVertex(t *testing.T) {
+	dag1 := typed.NewDAG[int, job]()
+
+	vertex1 := typed.NewVertex(1, job{"build"})
+
+	err := dag1.AddVertex(vertex1)
+	if err != nil {
+		t.Fatalf("Can't add vertex to DAG: %s", err)
+	}
+
+	if dag1.Order() != 1 {
+		t.Fatalf("DAG number of vertices expected to be 1 but got %d", dag1.Order())
+	}
+
+	err = dag1.AddVertex(typed.NewVertex(1, job{"test"}))
+	if err == nil {
+		t.Fatalf("Vertex already exists, AddVertex should fail but it doesn't")
+	}
+}
+
+func TestDAG_AddVertex_FailsIDCollision(t *testing.T) {
+	type key struct {
+		a, b string
+	}
+
+	dag1 := typed.NewDAG[key, job]()
+
+	// Both keys print as "{a b }".
+	vertex1 := typed.NewVertex(key{"a b", ""}, job{"one"})
+	err := dag1.AddVertex(vertex1)
+	if err != nil {
+		t.Fatalf("Can't add vertex to DAG: %s", err)
+	}
+
+	err = dag1.AddVertex(typed.NewVertex(key{"a", "b "}, job{"two"}))
+	if err == nil {
+		t.Fatalf("Vertex IDs collide, AddVertex should fail but it doesn't")
+	}
+
+	// The vertex added first keeps its untyped ID.
+	vertex, err := dag1.Untyped().GetVertex("{a b }")
+	if err != nil {
+		t.Fatalf("Can't get vertex from DAG: %s", err)
+	}
+	if v, _ := typed.VertexOf[key, job](vertex); v != vertex1 {
+		t.Fatalf("Expected untyped vertex to wrap vertex %v but got %v", vertex1.ID, v)
+	}
+}
+
+func TestDAG_Untyped(t *testing.T) {
+	dag1 := typed.NewDAG[int, string]()
+
+	vertex1 := typed.NewVertex(1, "one")
+	vertex2 := typed.NewVertex(2, "two")
+	for _, vertex := range []*typed.Vertex[int, string]{vertex1, vertex2} {
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+	}
+	err := dag1.AddEdge(vertex1, vertex2)
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+
+	untyped := dag1.Untyped()
+	if untyped.Order() != 2 || untyped.Size() != 1 {
+		t.Fatalf("Expected untyped graph to have 2 vertices and 1 edge but got %d and %d", untyped.Order(), untyped.Size())
+	}
+
+	// The untyped vertex holds the typed value, not the typed vertex.
+	vertex, err := untyped.GetVertex("1")
+	if err != nil {
+		t.Fatalf("Can't get vertex from DAG: %s", err)
+	}
+	if vertex.Value != "one" {
+		t.Fatalf("Expected untyped value to be %q but got %v", "one", vertex.Value)
+	}
+	if dag.ValueLabel(vertex) != "one" {
+		t.Fatalf("Expected value label to be %q but got %q", "one", dag.ValueLabel(vertex))
+	}
+
+	v, ok := typed.VertexOf[int, string](vertex)
+	if !ok || v != vertex1 {
+		t.Fatalf("Expected VertexOf to return vertex 1 but got %v, %v", v, ok)
+	}
+	_, ok = typed.VertexOf[string, string](vertex)
+	if ok {
+		t.Fatalf("Vertex types don't match, VertexOf should fail but it doesn't")
+	}
+
+	// Changes to the untyped copy are not seen by the typed graph.
+	vertex.Value = 1
+	vertex3 := dag.NewVertex("3", nil)
+	err = untyped.AddVertex(vertex3)
+	if err != nil {
+		t.Fatalf("Can't add vertex to DAG: %s", err)
+	}
+	err = untyped.AddEdge(vertex, vertex3)
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+	_, ok = typed.VertexOf[int, string](vertex3)
+	if ok {
+		t.Fatalf("Vertex 3 isn't typed, VertexOf should fail but it doesn't")
+	}
+
+	if vertex1.Value() != "one" {
+		t.Fatalf("Expected value1 to be %q but got %q", "one", vertex1.Value())
+	}
+	if dag1.Order() != 2 || dag1.Size() != 1 {
+		t.Fatalf("Expected typed graph to have 2 vertices and 1 edge but got %d and %d", dag1.Order(), dag1.Size())
+	}
+	successors, err := dag1.Successors(vertex1)
+	if err != nil {
+		t.Fatalf("Can't get successors: %s", err)
+	}
+	if !reflect.DeepEqual(values(successors), []string{"two"}) {
+		t.Fatalf("Expected successors to be [two] but got %v", values(successors))
+	}
+
+	// Changes to the typed graph are seen by new copies only.
+	vertex1.SetValue("uno")
+	if vertex.Value != 1 {
+		t.Fatalf("Expected untyped value to be 1 but got %v", vertex.Value)
+	}
+	vertex, _ = dag1.Untyped().GetVertex("1")
+	if vertex.Value != "uno" {
+		t.Fatalf("Expected untyped value to be %q but got %v", "uno", vertex.Value)
+	}
+}
+
+func TestDAG_DeleteVertex(t *testing.T) {
+	dag1 := typed.NewDAG[int, job]()
+
+	vertex1 := typed.NewVertex(1, job{})
+	vertex2 := typed.NewVertex(2, job{})
+
+	for _, vertex := range []*typed.Vertex[int, job]{vertex1, vertex2} {
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+	}
+	err := dag1.AddEdge(vertex1, vertex2)
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+
+	err = dag1.DeleteVertex(vertex1)
+	if err != nil {
+		t.Fatalf("Can't delete vertex from DAG: %s", err)
+	}
+
+	if dag1.Order() != 1 {
+		t.Fatalf("DAG number of vertices expected to be 1 but got %d", dag1.Order())
+	}
+	if vertex2.InDegree() != 0 {
+		t.Fatalf("Vertex2 InDegree expected to be 0 but got %d", vertex2.InDegree())
+	}
+	_, err = dag1.GetVertex(1)
+	if err == nil {
+		t.Fatalf("Vertex was deleted, GetVertex should fail but it doesn't")
+	}
+
+	err = dag1.DeleteVertex(vertex1)
+	if err == nil {
+		t.Fatalf("Vertex don't exist, DeleteVertex should fail but it doesn't")
+	}
+}
+
+func TestDAG_AddEdge_FailsCycle(t *testing.T) {
+	dag1 := typed.NewDAG[string, int]()
+
+	vertexA := typed.NewVertex("a", 1)
+	vertexB := typed.NewVertex("b", 2)
+
+	for _, vertex := range []*typed.Vertex[string, int]{vertexA, vertexB} {
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+	}
+	err := dag1.AddEdge(vertexA, vertexB)
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+
+	err = dag1.AddEdge(vertexB, vertexA)
+	cycleErr, ok := err.(*typed.CycleError[string, int])
+	if !ok {
+		t.Fatalf("Expected error to be a *typed.CycleError but got %v", err)
+	}
+	if len(cycleErr.Path) != 3 || cycleErr.Path[0] != vertexB || cycleErr.Path[1] != vertexA {
+		t.Fatalf("Unexpected cycle path: %s", err)
+	}
+	expectedMsg := "cycle detected: b -> a -> b"
+	if err.Error() != expectedMsg {
+		t.Fatalf("Expected error message to be %q but got %q", expectedMsg, err.Error())
+	}
+}
+
+func TestDAG_GetVertex(t *testing.T) {
+	dag1 := typed.NewDAG[int, string]()
+
+	err := dag1.AddVertex(typed.NewVertex(1, "one"))
+	if err != nil {
+		t.Fatalf("Can't add vertex to DAG: %s", err)
+	}
+
+	v1, err := dag1.GetVertex(1)
+	if err != nil {
+		t.Fatalf("Can't get vertex from DAG: %s", err)
+	}
+	if v1.Value() != "one" {
+		t.Fatalf("Expected value1 to be %q but got %q.", "one", v1.Value())
+	}
+
+	_, err = dag1.GetVertex(2)
+	if err == nil {
+		t.Fatalf("Vertex don't exist, GetVertex should fail but it doesn't")
+	}
+}
+
+func TestDAG_SuccessorsPredecessors(t *testing.T) {
+	dag1 := typed.NewDAG[int, string]()
+
+	vertex1 := typed.NewVertex(1, "one")
+	vertex2 := typed.NewVertex(2, "two")
+	vertex3 := typed.NewVertex(3, "three")
+
+	for _, vertex := range []*typed.Vertex[int, string]{vertex1, vertex2, vertex3} {
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+	}
+	err := dag1.AddEdge(vertex1, vertex2)
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+	err = dag1.AddEdge(vertex1, vertex3)
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+
+	successors, err := dag1.Successors(vertex1)
+	if err != nil {
+		t.Fatalf("Can't get successors: %s", err)
+	}
+	if !reflect.DeepEqual(values(successors), []string{"two", "three"}) {
+		t.Fatalf("Unexpected successors %v", values(successors))
+	}
+
+	predecessors, err := dag1.Predecessors(vertex3)
+	if err != nil {
+		t.Fatalf("Can't get predecessors: %s", err)
+	}
+	if !reflect.DeepEqual(values(predecessors), []string{"one"}) {
+		t.Fatalf("Unexpected predecessors %v", values(predecessors))
+	}
+
+	if len(dag1.SourceVertices()) != 1 {
+		t.Fatalf("Expected to have 1 Source vertex but got %d", len(dag1.SourceVertices()))
+	}
+	if len(dag1.SinkVertices()) != 2 {
+		t.Fatalf("Expected to have 2 Sink vertices but got %d", len(dag1.SinkVertices()))
+	}
+
+	err = dag1.DeleteEdge(vertex1, vertex3)
+	if err != nil {
+		t.Fatalf("Can't delete edge from DAG: %s", err)
+	}
+	if dag1.Size() != 1 {
+		t.Fatalf("Dag expected to have 1 edge but got %d", dag1.Size())
+	}
+}
+
+func TestDAG_Range(t *testing.T) {
+	dag1 := typed.NewDAG[int, string]()
+
+	for i, value := range []string{"zero", "one", "two"} {
+		err := dag1.AddVertex(typed.NewVertex(i, value))
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+	}
+
+	var visited []int
+	dag1.Range(func(v *typed.Vertex[int, string]) bool {
+		visited = append(visited, v.ID)
+		return v.ID < 1
+	})
+	if !reflect.DeepEqual(visited, []int{0, 1}) {
+		t.Fatalf("Expected Range to visit %v but got %v", []int{0, 1}, visited)
+	}
+
+	if !reflect.DeepEqual(values(dag1.Vertices()), []string{"zero", "one", "two"}) {
+		t.Fatalf("Unexpected vertices %v", values(dag1.Vertices()))
+	}
+}
+
+func TestDAG_TopologicalSort(t *testing.T) {
+	dag1 := typed.NewDAG[int, string]()
+
+	vertex1 := typed.NewVertex(1, "one")
+	vertex2 := typed.NewVertex(2, "two")
+	vertex3 := typed.NewVertex(3, "three")
+
+	for _, vertex := range []*typed.Vertex[int, string]{vertex3, vertex2, vertex1} {
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+	}
+	err := dag1.AddEdge(vertex1, vertex2)
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+	err = dag1.AddEdge(vertex2, vertex3)
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+
+	expected := []string{"one", "two", "three"}
+
+	sorted, err := dag1.TopologicalSort()
+	if err != nil {
+		t.Fatalf("Can't sort DAG: %s", err)
+	}
+	if !reflect.DeepEqual(values(sorted), expected) {
+		t.Fatalf("Expected sorted vertices to be %v but got %v", expected, values(sorted))
+	}
+
+	sorted, err = dag1.TopologicalSortStable()
+	if err != nil {
+		t.Fatalf("Can't sort DAG: %s", err)
+	}
+	if !reflect.DeepEqual(values(sorted), expected) {
+		t.Fatalf("Expected sorted vertices to be %v but got %v", expected, values(sorted))
+	}
+
+	generations, err := dag1.Generations()
+	if err != nil {
+		t.Fatalf("Can't get DAG generations: %s", err)
+	}
+	if len(generations) != 3 {
+		t.Fatalf("Expected 3 generations but got %d", len(generations))
+	}
+}
+
+func TestDAG_String(t *testing.T) {
+	dag1 := typed.NewDAG[int, string]()
+
+	vertex1 := typed.NewVertex(1, "one")
+	vertex2 := typed.NewVertex(2, "two")
+
+	for _, vertex := range []*typed.Vertex[int, string]{vertex1, vertex2} {
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+	}
+	err := dag1.AddEdge(vertex1, vertex2)
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+
+	expected := "DAG Vertices: 2 - Edges: 1\n" +
+		"Vertices:\n" +
+		"ID: 1 - Parents: 0 - Children: 1 - Value: one\n" +
+		"ID: 2 - Parents: 1 - Children: 0 - Value: two\n"
+	if dag1.String() != expected {
+		t.Fatalf("DAG stringer expected to be %q but got %q", expected, dag1.String())
+	}
+}
+
+// values return the values of the given vertices.
+func values[K comparable, V any](vertices []*typed.Vertex[K, V]) []V {
+	result := make([]V, len(vertices))
+	for i, vertex := range vertices {
+		result[i] = vertex.Value()
+	}
+
+	return result
+}
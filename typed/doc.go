@@ -0,0 +1,50 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+/*
+Package typed implements a type-safe directed acyclic graph ( DAG ) on top of
+package dag.
+
+Vertices are identified by a comparable key of type K and carry a value of
+type V, so neither IDs nor values need type assertions. The graph keeps the
+same guarantees as dag.DAG, which it uses underneath: edges closing a cycle
+are rejected and deletions keep both adjacency directions in sync.
+
+A copy of the graph as an untyped one is available from DAG.Untyped. Its
+vertices hold the typed values as Value, and their IDs are the keys printed
+by fmt.Sprint, so keys printing the same way can't be in the same graph.
+VertexOf maps the untyped vertices back to typed ones.
+
+Example
+
+	// Create the dag.
+	dag1 := typed.NewDAG[int, string]()
+
+	// Create the vertices.
+	vertex1 := typed.NewVertex(1, "one")
+	vertex2 := typed.NewVertex(2, "two")
+
+	// Add the vertices and the edge between them.
+	dag1.AddVertex(vertex1)
+	dag1.AddVertex(vertex2)
+	dag1.AddEdge(vertex1, vertex2)
+
+	// Successors are typed too.
+	successors, _ := dag1.Successors(vertex1)
+	fmt.Println(successors[0].Value()) // two
+*/
+package typed
@@ -0,0 +1,64 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package typed_test
+
+import (
+	"fmt"
+
+	"github.com/goombaio/dag/typed"
+)
+
+func ExampleDAG() {
+	dag1 := typed.NewDAG[string, int]()
+
+	vertex1 := typed.NewVertex("a", 1)
+	vertex2 := typed.NewVertex("b", 2)
+	vertex3 := typed.NewVertex("c", 3)
+
+	for _, vertex := range []*typed.Vertex[string, int]{vertex1, vertex2, vertex3} {
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			fmt.Printf("Can't add vertex to DAG: %s", err)
+			panic(err)
+		}
+	}
+
+	err := dag1.AddEdge(vertex1, vertex2)
+	if err != nil {
+		fmt.Printf("Can't add edge to DAG: %s", err)
+		panic(err)
+	}
+	err = dag1.AddEdge(vertex1, vertex3)
+	if err != nil {
+		fmt.Printf("Can't add edge to DAG: %s", err)
+		panic(err)
+	}
+
+	successors, err := dag1.Successors(vertex1)
+	if err != nil {
+		fmt.Printf("Can't get successors: %s", err)
+		panic(err)
+	}
+
+	sum := 0
+	for _, successor := range successors {
+		sum += successor.Value()
+	}
+	fmt.Println(sum)
+	// Output:
+	// 5
+}
@@ -0,0 +1,124 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package typed
+
+import (
+	"fmt"
+
+	"github.com/goombaio/dag"
+)
+
+// vertexKey is the Metadata key under which an untyped vertex points back to
+// the typed vertex wrapping it.
+const vertexKey = "typed.vertex"
+
+// Vertex type implements a vertex of a type-safe Directed Acyclic graph or
+// DAG.
+type Vertex[K comparable, V any] struct {
+	ID K
+
+	// vertex is the underlying untyped vertex. It holds the value of the
+	// vertex, and points back to this typed vertex from its Metadata.
+	vertex *dag.Vertex
+}
+
+// NewVertex creates a new vertex.
+func NewVertex[K comparable, V any](id K, value V) *Vertex[K, V] {
+	v := &Vertex[K, V]{
+		ID:     id,
+		vertex: dag.NewVertex(fmt.Sprint(id), value),
+	}
+	v.vertex.Metadata[vertexKey] = v
+
+	return v
+}
+
+// VertexOf return the typed vertex wrapping an untyped one, as found in the
+// graph returned by DAG.Untyped. It return false for any other vertex.
+func VertexOf[K comparable, V any](vertex *dag.Vertex) (*Vertex[K, V], bool) {
+	v, ok := vertex.Metadata[vertexKey].(*Vertex[K, V])
+
+	return v, ok
+}
+
+// Value return the value of the vertex.
+func (v *Vertex[K, V]) Value() V {
+	// Only NewVertex and SetValue set the untyped value, so it always holds
+	// a V, unless it is nil for an interface type.
+	value, _ := v.vertex.Value.(V)
+
+	return value
+}
+
+// SetValue sets the value of the vertex.
+func (v *Vertex[K, V]) SetValue(value V) {
+	v.vertex.Value = value
+}
+
+// Parents return the parents of the vertex in insertion order.
+func (v *Vertex[K, V]) Parents() []*Vertex[K, V] {
+	return typedVertices[K, V](v.vertex.Parents.Values())
+}
+
+// Children return the children of the vertex in insertion order.
+func (v *Vertex[K, V]) Children() []*Vertex[K, V] {
+	return typedVertices[K, V](v.vertex.Children.Values())
+}
+
+// Degree return the number of parents and children of the vertex
+func (v *Vertex[K, V]) Degree() int {
+	return v.vertex.Degree()
+}
+
+// InDegree return the number of parents of the vertex or the number of edges
+// entering on it.
+func (v *Vertex[K, V]) InDegree() int {
+	return v.vertex.InDegree()
+}
+
+// OutDegree return the number of children of the vertex or the number of edges
+// leaving it.
+func (v *Vertex[K, V]) OutDegree() int {
+	return v.vertex.OutDegree()
+}
+
+// String implements stringer interface and prints an string representation
+// of this instance.
+func (v *Vertex[K, V]) String() string {
+	result := fmt.Sprintf("ID: %v - Parents: %d - Children: %d - Value: %v\n", v.ID, v.InDegree(), v.OutDegree(), v.Value())
+
+	return result
+}
+
+// typedVertex return the typed vertex wrapping an untyped one of a typed
+// graph. Untyped vertices only reach the graph through DAG.AddVertex, so
+// they always wrap one.
+func typedVertex[K comparable, V any](vertex *dag.Vertex) *Vertex[K, V] {
+	return vertex.Metadata[vertexKey].(*Vertex[K, V])
+}
+
+// typedVertices return the typed vertices wrapping a list of untyped ones,
+// as stored in the Parents and Children sets.
+func typedVertices[K comparable, V any](vertices []interface{}) []*Vertex[K, V] {
+	result := make([]*Vertex[K, V], len(vertices))
+	for i, vertex := range vertices {
+		result[i] = typedVertex[K, V](vertex.(*dag.Vertex))
+	}
+
+	return result
+}
@@ -0,0 +1,79 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package typed_test
+
+import (
+	"testing"
+
+	"github.com/goombaio/dag/typed"
+)
+
+func TestVertex(t *testing.T) {
+	v := typed.NewVertex("1", 1)
+
+	if v.ID != "1" {
+		t.Fatalf("Vertex ID expected to be %q but got %q", "1", v.ID)
+	}
+	if v.Value() != 1 {
+		t.Fatalf("Vertex Value expected to be 1 but got %d", v.Value())
+	}
+	if v.Degree() != 0 {
+		t.Fatalf("Vertex Degree expected to be 0 but got %d", v.Degree())
+	}
+}
+
+func TestVertex_ParentsChildren(t *testing.T) {
+	dag1 := typed.NewDAG[string, int]()
+
+	vertex1 := typed.NewVertex("1", 1)
+	vertex2 := typed.NewVertex("2", 2)
+
+	for _, vertex := range []*typed.Vertex[string, int]{vertex1, vertex2} {
+		err := dag1.AddVertex(vertex)
+		if err != nil {
+			t.Fatalf("Can't add vertex to DAG: %s", err)
+		}
+	}
+	err := dag1.AddEdge(vertex1, vertex2)
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+
+	children := vertex1.Children()
+	if len(children) != 1 || children[0] != vertex2 {
+		t.Fatalf("Vertex1 Children expected to be [2] but got %v", children)
+	}
+	parents := vertex2.Parents()
+	if len(parents) != 1 || parents[0] != vertex1 {
+		t.Fatalf("Vertex2 Parents expected to be [1] but got %v", parents)
+	}
+	if vertex1.OutDegree() != 1 {
+		t.Fatalf("Vertex1 OutDegree expected to be 1 but got %d", vertex1.OutDegree())
+	}
+	if vertex2.InDegree() != 1 {
+		t.Fatalf("Vertex2 InDegree expected to be 1 but got %d", vertex2.InDegree())
+	}
+}
+
+func TestVertex_String(t *testing.T) {
+	v := typed.NewVertex(1, "one")
+
+	expected := "ID: 1 - Parents: 0 - Children: 0 - Value: one\n"
+	if v.String() != expected {
+		t.Fatalf("Vertex stringer expected to be %q but got %q", expected, v.String())
+	}
+}
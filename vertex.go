@@ -29,6 +29,16 @@ type Vertex struct {
 	Value    interface{}
 	Parents  *orderedset.OrderedSet
 	Children *orderedset.OrderedSet
+
+	// Metadata holds data about the vertex that is not part of its Value,
+	// such as execution settings or attributes read from a file. Keys
+	// should be namespaced by whoever sets them, like "executor.options".
+	Metadata map[string]interface{}
+
+	// Labels tag the vertex, such as with its owner or environment, to
+	// select it with VerticesByLabel. Once the vertex is in a graph, use
+	// SetVertexLabel and DeleteVertexLabel to change them.
+	Labels map[string]string
 }
 
 // NewVertex creates a new vertex.
@@ -38,6 +48,8 @@ func NewVertex(id string, value interface{}) *Vertex {
 		Parents:  orderedset.NewOrderedSet(),
 		Children: orderedset.NewOrderedSet(),
 		Value:    value,
+		Metadata: make(map[string]interface{}),
+		Labels:   make(map[string]string),
 	}
 
 	return v
@@ -0,0 +1,306 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package dag
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlGraph is the YAML representation of a graph written by WriteYAML.
+type yamlGraph struct {
+	Vertices []yamlVertex `yaml:"vertices"`
+}
+
+// yamlVertex is the YAML representation of a vertex written by WriteYAML.
+// Each dependency is either the ID of the vertex depended on, or a
+// yamlDependency if the edge has a label, weight or attributes.
+type yamlVertex struct {
+	ID        string        `yaml:"id"`
+	DependsOn []interface{} `yaml:"depends_on,flow,omitempty"`
+	Value     interface{}   `yaml:"value,omitempty"`
+}
+
+// yamlDependency is the YAML representation of a dependency whose edge
+// isn't just the defaults of AddEdge.
+type yamlDependency struct {
+	ID     string                 `yaml:"id"`
+	Label  string                 `yaml:"label,omitempty"`
+	Weight *float64               `yaml:"weight,omitempty"`
+	Attrs  map[string]interface{} `yaml:"attrs,omitempty"`
+}
+
+// yamlEntry is a vertex read by ReadYAML, kept with the nodes of its ID and
+// dependencies to report errors at their position.
+type yamlEntry struct {
+	vertex    *Vertex
+	id        *yaml.Node
+	dependsOn []*yamlEdge
+}
+
+// yamlEdge is a dependency read by ReadYAML, kept with the node of the ID
+// depended on to report errors at its position.
+type yamlEdge struct {
+	id     *yaml.Node
+	label  string
+	weight float64
+	attrs  map[string]interface{}
+}
+
+// ReadYAML builds a graph from a YAML pipeline definition, a document
+// listing its vertices in order, each one with its ID, the IDs of the
+// vertices it depends on and an optional value of any kind:
+//
+//	vertices:
+//	  - id: build
+//	    value:
+//	      command: make
+//	  - id: test
+//	    depends_on: [build]
+//	  - id: deploy
+//	    depends_on: [test, {id: build, label: artifacts, weight: 2, attrs: {path: dist}}]
+//
+// Every dependency becomes an edge from the vertex depended on to the
+// vertex depending on it, and may be defined before or after it. A
+// dependency is either the ID of the vertex depended on, or a mapping with
+// that ID and the label, weight and attributes of the edge. Values and
+// attributes are decoded as by gopkg.in/yaml.v3 into an interface{}.
+//
+// Errors in the definition, such as duplicate IDs, unknown dependencies or
+// dependencies closing a cycle, as a *CycleError, are returned as a
+// *ParseError with the position of the problem.
+func ReadYAML(r io.Reader) (*DAG, error) {
+	var document yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&document); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	d := NewDAG()
+	if len(document.Content) == 0 {
+		return d, nil
+	}
+
+	var entries []*yamlEntry
+
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, yamlErrorf(root, "expected a mapping")
+	}
+	for i := 0; i < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if key.Value != "vertices" {
+			return nil, yamlErrorf(key, "unknown field %s", key.Value)
+		}
+		if value.Kind != yaml.SequenceNode {
+			return nil, yamlErrorf(value, "expected a list of vertices")
+		}
+
+		for _, node := range value.Content {
+			entry, err := readYAMLVertex(node)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := d.GetVertex(entry.vertex.ID); err == nil {
+				return nil, yamlErrorf(entry.id, "duplicate vertex %s", entry.vertex.ID)
+			}
+
+			if err := d.AddVertex(entry.vertex); err != nil {
+				return nil, &ParseError{Line: entry.id.Line, Column: entry.id.Column, Err: err}
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	for _, entry := range entries {
+		for _, edge := range entry.dependsOn {
+			node := edge.id
+			parent, err := d.GetVertex(node.Value)
+			if err != nil {
+				return nil, yamlErrorf(node, "vertex %s depends on unknown vertex %s", entry.vertex.ID, node.Value)
+			}
+			if parent.Children.Contains(entry.vertex) {
+				continue
+			}
+			if err := d.AddEdgeWithAttrs(parent, entry.vertex, edge.label, edge.weight, edge.attrs); err != nil {
+				return nil, &ParseError{Line: node.Line, Column: node.Column, Err: err}
+			}
+		}
+	}
+
+	return d, nil
+}
+
+// readYAMLVertex reads a vertex from its YAML node.
+func readYAMLVertex(node *yaml.Node) (*yamlEntry, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, yamlErrorf(node, "expected a vertex")
+	}
+
+	entry := &yamlEntry{}
+	var value interface{}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		key, field := node.Content[i], node.Content[i+1]
+
+		switch key.Value {
+		case "id":
+			if field.Kind != yaml.ScalarNode || field.Value == "" {
+				return nil, yamlErrorf(field, "expected a vertex ID")
+			}
+			entry.id = field
+		case "depends_on":
+			if field.Kind != yaml.SequenceNode {
+				return nil, yamlErrorf(field, "expected a list of vertex IDs")
+			}
+			for _, dependency := range field.Content {
+				edge, err := readYAMLEdge(dependency)
+				if err != nil {
+					return nil, err
+				}
+				entry.dependsOn = append(entry.dependsOn, edge)
+			}
+		case "value":
+			if err := field.Decode(&value); err != nil {
+				return nil, yamlErrorf(field, "can't decode value: %w", err)
+			}
+		default:
+			return nil, yamlErrorf(key, "unknown field %s", key.Value)
+		}
+	}
+
+	if entry.id == nil {
+		return nil, yamlErrorf(node, "vertex without ID")
+	}
+	entry.vertex = NewVertex(entry.id.Value, value)
+
+	return entry, nil
+}
+
+// readYAMLEdge reads a dependency from its YAML node, either the ID of the
+// vertex depended on or a mapping with the edge data.
+func readYAMLEdge(node *yaml.Node) (*yamlEdge, error) {
+	edge := &yamlEdge{weight: 1}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		edge.id = node
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			key, field := node.Content[i], node.Content[i+1]
+
+			switch key.Value {
+			case "id":
+				edge.id = field
+			case "label":
+				if field.Kind != yaml.ScalarNode {
+					return nil, yamlErrorf(field, "expected an edge label")
+				}
+				edge.label = field.Value
+			case "weight":
+				if err := field.Decode(&edge.weight); err != nil {
+					return nil, yamlErrorf(field, "expected an edge weight")
+				}
+			case "attrs":
+				if field.Kind != yaml.MappingNode {
+					return nil, yamlErrorf(field, "expected edge attributes")
+				}
+				if err := field.Decode(&edge.attrs); err != nil {
+					return nil, yamlErrorf(field, "can't decode edge attributes: %w", err)
+				}
+			default:
+				return nil, yamlErrorf(key, "unknown field %s", key.Value)
+			}
+		}
+		if edge.id == nil {
+			return nil, yamlErrorf(node, "dependency without vertex ID")
+		}
+	}
+
+	if edge.id == nil || edge.id.Kind != yaml.ScalarNode {
+		return nil, yamlErrorf(node, "expected a vertex ID")
+	}
+
+	return edge, nil
+}
+
+// ReadYAMLFile builds a graph from the YAML pipeline definition in the named
+// file, as described in ReadYAML. A *ParseError points to the file name.
+func ReadYAMLFile(name string) (*DAG, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	d, err := ReadYAML(f)
+	var parseErr *ParseError
+	switch {
+	case errors.As(err, &parseErr):
+		parseErr.File = name
+		return nil, parseErr
+	case err != nil:
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return d, nil
+}
+
+// WriteYAML writes the graph to w as a YAML pipeline definition, in the
+// format read by ReadYAML. Vertices are written in insertion order, and
+// their dependencies in the order of their Parents, with the label, weight
+// and attributes of their edge unless they are the defaults of AddEdge.
+func (d *DAG) WriteYAML(w io.Writer) error {
+	graph := yamlGraph{Vertices: []yamlVertex{}}
+	for _, vertex := range d.Vertices() {
+		v := yamlVertex{ID: vertex.ID, Value: vertex.Value}
+
+		edges, _ := d.InEdges(vertex)
+		for _, edge := range edges {
+			if edge.Label == "" && edge.Weight == 1 && len(edge.Attrs) == 0 {
+				v.DependsOn = append(v.DependsOn, edge.Tail.ID)
+				continue
+			}
+
+			dependency := yamlDependency{ID: edge.Tail.ID, Label: edge.Label, Attrs: edge.Attrs}
+			if edge.Weight != 1 {
+				weight := edge.Weight
+				dependency.Weight = &weight
+			}
+			v.DependsOn = append(v.DependsOn, dependency)
+		}
+
+		graph.Vertices = append(graph.Vertices, v)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&graph); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// yamlErrorf return a ParseError at the position of the given node.
+func yamlErrorf(node *yaml.Node, format string, args ...interface{}) error {
+	return &ParseError{Line: node.Line, Column: node.Column, Err: fmt.Errorf(format, args...)}
+}
@@ -0,0 +1,272 @@
+// Copyright 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package dag_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goombaio/dag"
+)
+
+func TestReadYAML(t *testing.T) {
+	src := `# Nightly pipeline.
+vertices:
+  - id: build
+    value:
+      command: make
+      env: [CI=1]
+  - id: test
+    depends_on: [build, lint]
+  - id: lint
+    value: 3
+  - id: deploy
+    depends_on:
+      - test
+      - test
+`
+
+	dag1, err := dag.ReadYAML(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Can't read YAML: %s", err)
+	}
+
+	expectedVertices := []string{"build", "test", "lint", "deploy"}
+	if !reflect.DeepEqual(vertexIDs(dag1.Vertices()), expectedVertices) {
+		t.Fatalf("Expected vertices to be %v but got %v", expectedVertices, vertexIDs(dag1.Vertices()))
+	}
+
+	expectedEdges := []string{"build->test", "test->deploy", "lint->test"}
+	if !reflect.DeepEqual(edgeIDs(dag1), expectedEdges) {
+		t.Fatalf("Expected edges to be %v but got %v", expectedEdges, edgeIDs(dag1))
+	}
+
+	build, _ := dag1.GetVertex("build")
+	expectedValue := map[string]interface{}{"command": "make", "env": []interface{}{"CI=1"}}
+	if !reflect.DeepEqual(build.Value, expectedValue) {
+		t.Fatalf("Expected build value to be %v but got %v", expectedValue, build.Value)
+	}
+
+	lint, _ := dag1.GetVertex("lint")
+	if lint.Value != 3 {
+		t.Fatalf("Expected lint value to be 3 but got %v", lint.Value)
+	}
+
+	test, _ := dag1.GetVertex("test")
+	if test.Value != nil {
+		t.Fatalf("Expected test value to be nil but got %v", test.Value)
+	}
+}
+
+func TestReadYAML_Empty(t *testing.T) {
+	for _, src := range []string{"", "vertices: []\n"} {
+		dag1, err := dag.ReadYAML(strings.NewReader(src))
+		if err != nil {
+			t.Fatalf("Can't read YAML %q: %s", src, err)
+		}
+		if dag1.Order() != 0 {
+			t.Fatalf("Expected DAG read from %q to be empty but got %d vertices", src, dag1.Order())
+		}
+	}
+}
+
+func TestReadYAML_Errors(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{"- id: a\n", "1:1: expected a mapping"},
+		{"steps: []\n", "1:1: unknown field steps"},
+		{"vertices: a\n", "1:11: expected a list of vertices"},
+		{"vertices:\n  - a\n", "2:5: expected a vertex"},
+		{"vertices:\n  - value: 1\n", "2:5: vertex without ID"},
+		{"vertices:\n  - id: [a]\n", "2:9: expected a vertex ID"},
+		{"vertices:\n  - id: a\n    depends-on: [b]\n", "3:5: unknown field depends-on"},
+		{"vertices:\n  - id: a\n    depends_on: b\n", "3:17: expected a list of vertex IDs"},
+		{"vertices:\n  - id: a\n    depends_on: [{b: 1}]\n", "3:19: unknown field b"},
+		{"vertices:\n  - id: a\n    depends_on: [[b]]\n", "3:18: expected a vertex ID"},
+		{"vertices:\n  - id: a\n    depends_on: [{label: x}]\n", "3:18: dependency without vertex ID"},
+		{"vertices:\n  - id: a\n    depends_on: [{id: b, weight: x}]\n", "3:34: expected an edge weight"},
+		{"vertices:\n  - id: a\n    depends_on: [{id: b, label: [x]}]\n", "3:33: expected an edge label"},
+		{"vertices:\n  - id: a\n    depends_on: [{id: b, attrs: x}]\n", "3:33: expected edge attributes"},
+		{"vertices:\n  - id: a\n  - id: b\n  - id: a\n", "4:9: duplicate vertex a"},
+		{"vertices:\n  - id: a\n    depends_on: [b]\n", "3:18: vertex a depends on unknown vertex b"},
+		{"vertices:\n  - id: a\n    depends_on: [b]\n  - id: b\n    depends_on: [a]\n", "5:18: cycle detected: a -> b -> a"},
+		{"vertices:\n  - id: a\n    depends_on: [a]\n", "3:18: cycle detected: a -> a"},
+	}
+
+	for _, test := range tests {
+		_, err := dag.ReadYAML(strings.NewReader(test.src))
+		if err == nil {
+			t.Fatalf("YAML %q is invalid, ReadYAML should fail but it doesn't", test.src)
+		}
+
+		var parseErr *dag.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("Expected error for %q to be a *dag.ParseError but got %T", test.src, err)
+		}
+		if err.Error() != test.expected {
+			t.Fatalf("Expected error message for %q to be %q but got %q", test.src, test.expected, err.Error())
+		}
+	}
+
+	_, err := dag.ReadYAML(strings.NewReader("vertices:\n  - id: a\n    depends_on: [a]\n"))
+	var cycleErr *dag.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected error to wrap a *dag.CycleError but got %v", err)
+	}
+
+	_, err = dag.ReadYAML(strings.NewReader("vertices: [\n"))
+	if err == nil {
+		t.Fatalf("YAML is malformed, ReadYAML should fail but it doesn't")
+	}
+}
+
+func TestReadYAMLFile(t *testing.T) {
+	dir := t.TempDir()
+
+	name := filepath.Join(dir, "pipeline.yaml")
+	err := os.WriteFile(name, []byte("vertices:\n  - id: a\n  - id: b\n    depends_on: [a, c]\n"), 0o644)
+	if err != nil {
+		t.Fatalf("Can't write pipeline file: %s", err)
+	}
+
+	_, err = dag.ReadYAMLFile(name)
+	expected := name + ":4:21: vertex b depends on unknown vertex c"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected error message to be %q but got %v", expected, err)
+	}
+
+	name = filepath.Join(dir, "malformed.yaml")
+	err = os.WriteFile(name, []byte("vertices: [\n"), 0o644)
+	if err != nil {
+		t.Fatalf("Can't write pipeline file: %s", err)
+	}
+
+	_, err = dag.ReadYAMLFile(name)
+	if err == nil || !strings.HasPrefix(err.Error(), name+": ") {
+		t.Fatalf("Expected error message to start with the file name but got %v", err)
+	}
+
+	_, err = dag.ReadYAMLFile(filepath.Join(dir, "missing.yaml"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected error to be os.ErrNotExist but got %v", err)
+	}
+}
+
+func TestDAG_WriteYAML(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"build", "lint", "test", "deploy"},
+		[][2]string{{"build", "test"}, {"lint", "test"}, {"test", "deploy"}},
+	)
+	vertices["build"].Value = map[string]interface{}{"command": "make"}
+	vertices["lint"].Value = 0
+
+	var buf bytes.Buffer
+	err := dag1.WriteYAML(&buf)
+	if err != nil {
+		t.Fatalf("Can't write YAML: %s", err)
+	}
+
+	expected := `vertices:
+  - id: build
+    value:
+      command: make
+  - id: lint
+    value: 0
+  - id: test
+    depends_on: [build, lint]
+  - id: deploy
+    depends_on: [test]
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected YAML to be %q but got %q", expected, buf.String())
+	}
+
+	dag2, err := dag.ReadYAML(&buf)
+	if err != nil {
+		t.Fatalf("Can't read YAML: %s", err)
+	}
+	if !reflect.DeepEqual(edgeIDs(dag2), edgeIDs(dag1)) {
+		t.Fatalf("Expected edges to round trip to %v but got %v", edgeIDs(dag1), edgeIDs(dag2))
+	}
+	lint, _ := dag2.GetVertex("lint")
+	if lint.Value != 0 {
+		t.Fatalf("Expected lint value to round trip to 0 but got %v", lint.Value)
+	}
+}
+
+func TestDAG_WriteYAML_Edges(t *testing.T) {
+	dag1, vertices := newTestDAG(t,
+		[]string{"build", "test", "deploy"},
+		[][2]string{{"build", "test"}, {"test", "deploy"}},
+	)
+	err := dag1.AddEdgeWithAttrs(vertices["build"], vertices["deploy"], "artifacts", 2.5, map[string]interface{}{"path": "dist"})
+	if err != nil {
+		t.Fatalf("Can't add edge to DAG: %s", err)
+	}
+	edge, _ := dag1.GetEdge(vertices["build"], vertices["test"])
+	edge.Label = "binaries"
+
+	var buf bytes.Buffer
+	err = dag1.WriteYAML(&buf)
+	if err != nil {
+		t.Fatalf("Can't write YAML: %s", err)
+	}
+
+	expected := `vertices:
+  - id: build
+  - id: test
+    depends_on: [{id: build, label: binaries}]
+  - id: deploy
+    depends_on: [test, {id: build, label: artifacts, weight: 2.5, attrs: {path: dist}}]
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected YAML to be %q but got %q", expected, buf.String())
+	}
+
+	dag2, err := dag.ReadYAML(&buf)
+	if err != nil {
+		t.Fatalf("Can't read YAML: %s", err)
+	}
+	if !reflect.DeepEqual(edgeIDs(dag2), edgeIDs(dag1)) {
+		t.Fatalf("Expected edges to round trip to %v but got %v", edgeIDs(dag1), edgeIDs(dag2))
+	}
+	for i, edge2 := range dag2.Edges() {
+		edge := dag1.Edges()[i]
+		if edge2.Label != edge.Label || edge2.Weight != edge.Weight || !reflect.DeepEqual(edge2.Attrs, edge.Attrs) {
+			t.Fatalf("Expected edge %s to round trip to %s but got %s", edge, edge, edge2)
+		}
+	}
+}
+
+func TestDAG_WriteYAML_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	err := dag.NewDAG().WriteYAML(&buf)
+	if err != nil {
+		t.Fatalf("Can't write YAML: %s", err)
+	}
+
+	if buf.String() != "vertices: []\n" {
+		t.Fatalf("Expected YAML to be %q but got %q", "vertices: []\n", buf.String())
+	}
+}